@@ -0,0 +1,82 @@
+package numa
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// AccessAttributes represent ACPI HMAT performance of NUMA node memory
+// as seen from its best performing initiator nodes.
+// Access class 0 considers all initiators, class 1 only CPU initiators.
+type AccessAttributes struct {
+	Class      int     `json:"class" yaml:"class"`
+	Initiators NodeSet `json:"initiators" yaml:"initiators"`
+	// ReadBandwidth and WriteBandwidth are in MB/s.
+	ReadBandwidth  uint64 `json:"read_bandwidth" yaml:"read_bandwidth"`
+	WriteBandwidth uint64 `json:"write_bandwidth" yaml:"write_bandwidth"`
+	// ReadLatency and WriteLatency are in nanoseconds.
+	ReadLatency  uint64 `json:"read_latency" yaml:"read_latency"`
+	WriteLatency uint64 `json:"write_latency" yaml:"write_latency"`
+}
+
+func parseAccessAttributes(fsys fs.FS, nodePath string) ([]AccessAttributes, error) {
+	dir, err := fs.ReadDir(fsys, nodePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var attrs []AccessAttributes
+	for _, i := range dir {
+		// access0
+		if !i.IsDir() || !strings.HasPrefix(i.Name(), "access") {
+			continue
+		}
+
+		class, err := strconv.Atoi(strings.TrimPrefix(i.Name(), "access"))
+		if err != nil {
+			continue
+		}
+
+		initiatorsPath := path.Join(nodePath, i.Name(), "initiators")
+		initiators, err := fs.ReadDir(fsys, initiatorsPath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		a := AccessAttributes{Class: class}
+		for _, j := range initiators {
+			if !strings.HasPrefix(j.Name(), "node") {
+				continue
+			}
+
+			id, err := strconv.Atoi(strings.TrimPrefix(j.Name(), "node"))
+			if err != nil {
+				return nil, fmt.Errorf("convert initiator %q: %w", j.Name(), err)
+			}
+			a.Initiators.Add(id)
+		}
+
+		for name, v := range map[string]*uint64{
+			"read_bandwidth":  &a.ReadBandwidth,
+			"write_bandwidth": &a.WriteBandwidth,
+			"read_latency":    &a.ReadLatency,
+			"write_latency":   &a.WriteLatency,
+		} {
+			*v, err = readUint64(fsys, path.Join(initiatorsPath, name))
+			if err != nil && !os.IsNotExist(err) {
+				return nil, err
+			}
+		}
+
+		attrs = append(attrs, a)
+	}
+
+	return attrs, nil
+}
@@ -0,0 +1,152 @@
+package numa
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"runtime"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+const wordBits = int(unsafe.Sizeof(uintptr(0)) * 8)
+
+var (
+	pinnedMu sync.Mutex
+	// pinned keeps affinity masks of threads before PinToNode keyed by thread ID.
+	pinned = make(map[int][]uintptr)
+)
+
+// PinToNode locks the calling goroutine to its OS thread and restricts
+// the thread to CPUs of NUMA node. Use UnpinThread to restore previous affinity.
+// Pinning already pinned thread only changes its CPUs, single UnpinThread
+// restores affinity the thread had before the first PinToNode.
+func PinToNode(node int) error {
+	cpus, err := parseCpuList(newOptions(nil).sys, path.Join("devices/system/node", fmt.Sprintf("node%d", node), "cpulist"))
+	if err != nil {
+		return fmt.Errorf("parse cpulist: %w", err)
+	}
+	if len(cpus) == 0 {
+		return fmt.Errorf("node %d has no CPUs", node)
+	}
+
+	runtime.LockOSThread()
+	tid := syscall.Gettid()
+
+	pinnedMu.Lock()
+	defer pinnedMu.Unlock()
+
+	// The goroutine is locked once per pinned thread as UnpinThread unlocks it once.
+	if _, ok := pinned[tid]; ok {
+		runtime.UnlockOSThread()
+		return setAffinity(tid, cpuMask(cpus))
+	}
+
+	previous, err := getAffinity(tid)
+	if err != nil {
+		runtime.UnlockOSThread()
+		return err
+	}
+
+	if err := setAffinity(tid, cpuMask(cpus)); err != nil {
+		runtime.UnlockOSThread()
+		return err
+	}
+	pinned[tid] = previous
+
+	return nil
+}
+
+// UnpinThread restores affinity of the calling thread saved by PinToNode
+// and unlocks the calling goroutine from its OS thread.
+func UnpinThread() error {
+	tid := syscall.Gettid()
+
+	pinnedMu.Lock()
+	defer pinnedMu.Unlock()
+
+	previous, ok := pinned[tid]
+	if !ok {
+		return errors.New("thread is not pinned")
+	}
+
+	// The mask is kept until affinity is restored, so UnpinThread could be retried.
+	if err := setAffinity(tid, previous); err != nil {
+		return err
+	}
+	delete(pinned, tid)
+
+	runtime.UnlockOSThread()
+
+	return nil
+}
+
+// GetThreadAffinity returns CPUs thread tid is allowed to run on.
+// Tid 0 means the calling thread.
+func GetThreadAffinity(tid int) (CPUSet, error) {
+	mask, err := getAffinity(tid)
+	if err != nil {
+		return CPUSet{}, err
+	}
+
+	var cpus CPUSet
+	for word, w := range mask {
+		for bit := 0; w != 0; bit++ {
+			if w&1 != 0 {
+				cpus.Add(word*wordBits + bit)
+			}
+			w >>= 1
+		}
+	}
+
+	return cpus, nil
+}
+
+// SetThreadAffinity restricts thread tid to CPUs. Tid 0 means the calling thread.
+func SetThreadAffinity(tid int, cpus []int) error {
+	return setAffinity(tid, cpuMask(cpus))
+}
+
+func getAffinity(tid int) ([]uintptr, error) {
+	// Kernel mask could be larger than the guess, grow it until kernel accepts.
+	for words := 1024 / wordBits; ; words *= 2 {
+		mask := make([]uintptr, words)
+		n, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_GETAFFINITY,
+			uintptr(tid), uintptr(len(mask)*wordBits/8), uintptr(unsafe.Pointer(&mask[0])))
+		if errno == syscall.EINVAL && words < 1<<16 {
+			continue
+		}
+		if errno != 0 {
+			return nil, fmt.Errorf("sched_getaffinity %d: %w", tid, errno)
+		}
+
+		return mask[:int(n)/(wordBits/8)], nil
+	}
+}
+
+func setAffinity(tid int, mask []uintptr) error {
+	if len(mask) == 0 {
+		return fmt.Errorf("sched_setaffinity %d: empty mask", tid)
+	}
+
+	_, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_SETAFFINITY,
+		uintptr(tid), uintptr(len(mask)*wordBits/8), uintptr(unsafe.Pointer(&mask[0])))
+	if errno != 0 {
+		return fmt.Errorf("sched_setaffinity %d: %w", tid, errno)
+	}
+
+	return nil
+}
+
+func cpuMask(cpus []int) []uintptr {
+	var mask []uintptr
+	for _, cpu := range cpus {
+		word := cpu / wordBits
+		for len(mask) <= word {
+			mask = append(mask, 0)
+		}
+		mask[word] |= 1 << (uint(cpu) % uint(wordBits))
+	}
+	return mask
+}
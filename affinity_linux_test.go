@@ -0,0 +1,50 @@
+package numa
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestPinToNodeTwice(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	ids, err := NodeIDs()
+	if err != nil || len(ids) == 0 {
+		t.Skip("requires NUMA node")
+	}
+	node := ids[0]
+
+	original, err := GetThreadAffinity(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 2 {
+		if err := PinToNode(node); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pinnedMu.Lock()
+	count := len(pinned)
+	pinnedMu.Unlock()
+	if count != 1 {
+		t.Errorf("%d threads pinned, want 1", count)
+	}
+
+	if err := UnpinThread(); err != nil {
+		t.Fatal(err)
+	}
+	if err := UnpinThread(); err == nil {
+		t.Error("second UnpinThread returned no error")
+	}
+
+	restored, err := GetThreadAffinity(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !restored.Equal(original) {
+		t.Errorf("affinity = %s, want %s", restored, original)
+	}
+}
@@ -0,0 +1,109 @@
+package numa
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+var (
+	procGetCurrentThread       = kernel32.NewProc("GetCurrentThread")
+	procGetCurrentThreadId     = kernel32.NewProc("GetCurrentThreadId")
+	procSetThreadGroupAffinity = kernel32.NewProc("SetThreadGroupAffinity")
+)
+
+var (
+	pinnedMu sync.Mutex
+	// pinned keeps group affinity of threads before PinToNode keyed by thread ID.
+	pinned = make(map[uintptr]groupAffinity)
+)
+
+// PinToNode locks the calling goroutine to its OS thread and restricts
+// the thread to CPUs of NUMA node. Use UnpinThread to restore previous affinity.
+// Pinning already pinned thread only changes its CPUs, single UnpinThread
+// restores affinity the thread had before the first PinToNode.
+// Thread runs in single processor group, only the first group of node
+// is used if node spans several groups.
+func PinToNode(node int) error {
+	affinities, err := nodeAffinities()
+	if err != nil {
+		return err
+	}
+
+	var affinity groupAffinity
+	for _, g := range affinities[node] {
+		if g.Mask != 0 {
+			affinity = g
+			break
+		}
+	}
+	if affinity.Mask == 0 {
+		return fmt.Errorf("node %d has no CPUs", node)
+	}
+
+	runtime.LockOSThread()
+	tid, _, _ := procGetCurrentThreadId.Call()
+
+	pinnedMu.Lock()
+	defer pinnedMu.Unlock()
+
+	// The goroutine is locked once per pinned thread as UnpinThread unlocks it once.
+	if _, ok := pinned[tid]; ok {
+		runtime.UnlockOSThread()
+		_, err := setThreadGroupAffinity(affinity)
+		return err
+	}
+
+	previous, err := setThreadGroupAffinity(affinity)
+	if err != nil {
+		runtime.UnlockOSThread()
+		return err
+	}
+	pinned[tid] = previous
+
+	return nil
+}
+
+// UnpinThread restores affinity of the calling thread saved by PinToNode
+// and unlocks the calling goroutine from its OS thread.
+func UnpinThread() error {
+	tid, _, _ := procGetCurrentThreadId.Call()
+
+	pinnedMu.Lock()
+	defer pinnedMu.Unlock()
+
+	previous, ok := pinned[tid]
+	if !ok {
+		return errors.New("thread is not pinned")
+	}
+
+	// The affinity is kept until it's restored, so UnpinThread could be retried.
+	if _, err := setThreadGroupAffinity(previous); err != nil {
+		return err
+	}
+	delete(pinned, tid)
+
+	runtime.UnlockOSThread()
+
+	return nil
+}
+
+// setThreadGroupAffinity sets affinity of the calling thread and returns the previous one.
+func setThreadGroupAffinity(affinity groupAffinity) (groupAffinity, error) {
+	if err := procSetThreadGroupAffinity.Find(); err != nil {
+		return groupAffinity{}, fmt.Errorf("%w: %w", ErrUnsupportedKernel, err)
+	}
+
+	thread, _, _ := procGetCurrentThread.Call()
+
+	var previous groupAffinity
+	r, _, err := procSetThreadGroupAffinity.Call(thread,
+		uintptr(unsafe.Pointer(&affinity)), uintptr(unsafe.Pointer(&previous)))
+	if r == 0 {
+		return groupAffinity{}, fmt.Errorf("SetThreadGroupAffinity group %d mask %#x: %w", affinity.Group, affinity.Mask, err)
+	}
+
+	return previous, nil
+}
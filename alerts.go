@@ -0,0 +1,132 @@
+package numa
+
+import (
+	"sync"
+	"time"
+)
+
+// NodeMetrics represent node information with counter rates for alert evaluation.
+type NodeMetrics struct {
+	Node Node
+	// Rates are per-second counter rates, see Rates. Could be nil.
+	Rates map[string]float64
+}
+
+// Condition represent alert condition on node metric with hysteresis.
+type Condition struct {
+	Name   string
+	Metric func(NodeMetrics) float64
+	// Below makes condition fire when metric is below Threshold,
+	// otherwise it fires when metric is above Threshold.
+	Below     bool
+	Threshold float64
+	// Clear is value metric must cross back to clear fired condition,
+	// Threshold is used if Clear is zero.
+	Clear float64
+}
+
+// AlertEvent represent condition firing or clearing on node.
+type AlertEvent struct {
+	Time      time.Time
+	Condition string
+	Node      int
+	// Firing is true when condition fired and false when it cleared.
+	Firing bool
+	Value  float64
+}
+
+// MemAvailableBelow returns condition firing when node MemAvailable is below
+// bytes and clearing when it's at or above clearBytes.
+func MemAvailableBelow(bytes, clearBytes uint64) Condition {
+	return Condition{
+		Name: "mem_available_below",
+		Metric: func(m NodeMetrics) float64 {
+			return float64(m.Node.MemAvailable)
+		},
+		Below:     true,
+		Threshold: float64(bytes),
+		Clear:     float64(clearBytes),
+	}
+}
+
+// RateAbove returns condition firing when per-second rate of counter, e.g. "numa_miss",
+// is above rate and clearing when it's at or below clearRate.
+func RateAbove(counter string, rate, clearRate float64) Condition {
+	return Condition{
+		Name: counter + "_rate_above",
+		Metric: func(m NodeMetrics) float64 {
+			return m.Rates[counter]
+		},
+		Threshold: rate,
+		Clear:     clearRate,
+	}
+}
+
+// Alerts evaluates registered conditions on every node and calls callbacks
+// when conditions fire or clear. It's safe for concurrent use.
+type Alerts struct {
+	mu    sync.Mutex
+	rules []*alertRule
+}
+
+type alertRule struct {
+	condition Condition
+	callback  func(AlertEvent)
+	// firing keeps nodes the condition fired on.
+	firing map[int]bool
+}
+
+// NewAlerts returns Alerts without conditions.
+func NewAlerts() *Alerts {
+	return &Alerts{}
+}
+
+// Register adds condition with callback called when it fires or clears on a node.
+func (a *Alerts) Register(c Condition, callback func(AlertEvent)) {
+	if c.Clear == 0 {
+		c.Clear = c.Threshold
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.rules = append(a.rules, &alertRule{condition: c, callback: callback, firing: make(map[int]bool)})
+}
+
+// Evaluate checks conditions against node metrics sampled at t.
+// Callbacks are called synchronously.
+func (a *Alerts) Evaluate(t time.Time, metrics []NodeMetrics) {
+	a.mu.Lock()
+	var events []AlertEvent
+	var callbacks []func(AlertEvent)
+	for _, r := range a.rules {
+		c := r.condition
+		for _, m := range metrics {
+			v := c.Metric(m)
+			firing := r.firing[m.Node.ID]
+
+			var changed bool
+			switch {
+			case !firing && c.Below && v < c.Threshold,
+				!firing && !c.Below && v > c.Threshold:
+				changed = true
+			case firing && c.Below && v >= c.Clear,
+				firing && !c.Below && v <= c.Clear:
+				changed = true
+			}
+			if !changed {
+				continue
+			}
+
+			r.firing[m.Node.ID] = !firing
+			events = append(events, AlertEvent{Time: t, Condition: c.Name, Node: m.Node.ID, Firing: !firing, Value: v})
+			callbacks = append(callbacks, r.callback)
+		}
+	}
+	a.mu.Unlock()
+
+	// Callbacks are called without the lock so they could register conditions.
+	for i, e := range events {
+		callbacks[i](e)
+	}
+}
@@ -0,0 +1,78 @@
+package numa
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// AllowedCPUs returns CPUs the current process is allowed to run on.
+func AllowedCPUs() ([]int, error) {
+	cpus, err := ProcessAllowedCPUs(0)
+	if err != nil {
+		return nil, err
+	}
+
+	return cpus.IDs(), nil
+}
+
+// AllowedNodes returns nodes the current process is allowed to allocate memory on.
+func AllowedNodes() (NodeSet, error) {
+	return ProcessAllowedNodes(0)
+}
+
+// ProcessAllowedCPUs returns CPUs process is allowed to run on.
+// Pid 0 means the current process.
+func ProcessAllowedCPUs(pid int) (CPUSet, error) {
+	list, err := readStatusField(procPath(pid, "status"), "Cpus_allowed_list")
+	if err != nil {
+		return CPUSet{}, err
+	}
+
+	cpus, err := ParseCPUSet(list)
+	if err != nil {
+		return CPUSet{}, fmt.Errorf("parse Cpus_allowed_list: %w", err)
+	}
+
+	return cpus, nil
+}
+
+// ProcessAllowedNodes returns nodes process is allowed to allocate memory on.
+// Pid 0 means the current process.
+func ProcessAllowedNodes(pid int) (NodeSet, error) {
+	list, err := readStatusField(procPath(pid, "status"), "Mems_allowed_list")
+	if err != nil {
+		return NodeSet{}, err
+	}
+
+	nodes, err := ParseNodeSet(list)
+	if err != nil {
+		return NodeSet{}, fmt.Errorf("parse Mems_allowed_list: %w", err)
+	}
+
+	return nodes, nil
+}
+
+func readStatusField(path, name string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// Cpus_allowed_list:	0-31
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if ok && key == name {
+			return strings.TrimSpace(value), nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	return "", fmt.Errorf("%s not found in %s", name, path)
+}
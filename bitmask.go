@@ -0,0 +1,233 @@
+package numa
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// bitmask is a variable-length set of non-negative integers.
+type bitmask []uint64
+
+// set sets i in place, it's used only on bitmasks not shared with other sets.
+func (b *bitmask) set(i int) {
+	word := i / 64
+	for len(*b) <= word {
+		*b = append(*b, 0)
+	}
+	(*b)[word] |= 1 << (uint(i) % 64)
+}
+
+// with returns bitmask with i set. b isn't modified as it could be shared
+// with copies of the set, new bitmask is allocated if i isn't set in b.
+func (b bitmask) with(i int) bitmask {
+	if b.isSet(i) {
+		return b
+	}
+
+	result := make(bitmask, max(len(b), i/64+1))
+	copy(result, b)
+	result.set(i)
+	return result
+}
+
+func (b bitmask) isSet(i int) bool {
+	if i < 0 {
+		return false
+	}
+	word := i / 64
+	if word >= len(b) {
+		return false
+	}
+	return b[word]&(1<<(uint(i)%64)) != 0
+}
+
+func (b bitmask) count() int {
+	var n int
+	for _, w := range b {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+func (b bitmask) ids() []int {
+	var ids []int
+	for word, w := range b {
+		for w != 0 {
+			bit := bits.TrailingZeros64(w)
+			ids = append(ids, word*64+bit)
+			w &^= 1 << uint(bit)
+		}
+	}
+	return ids
+}
+
+func (b bitmask) union(other bitmask) bitmask {
+	long, short := b, other
+	if len(short) > len(long) {
+		long, short = short, long
+	}
+
+	result := make(bitmask, len(long))
+	copy(result, long)
+	for i, w := range short {
+		result[i] |= w
+	}
+	return result.trim()
+}
+
+func (b bitmask) intersect(other bitmask) bitmask {
+	result := make(bitmask, min(len(b), len(other)))
+	for i := range result {
+		result[i] = b[i] & other[i]
+	}
+	return result.trim()
+}
+
+func (b bitmask) difference(other bitmask) bitmask {
+	result := make(bitmask, len(b))
+	copy(result, b)
+	for i := range result {
+		if i < len(other) {
+			result[i] &^= other[i]
+		}
+	}
+	return result.trim()
+}
+
+func (b bitmask) equal(other bitmask) bool {
+	b, other = b.trim(), other.trim()
+	if len(b) != len(other) {
+		return false
+	}
+	for i := range b {
+		if b[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// trim removes trailing zero words.
+func (b bitmask) trim() bitmask {
+	for len(b) > 0 && b[len(b)-1] == 0 {
+		b = b[:len(b)-1]
+	}
+	return b
+}
+
+// mask formats bitmask in kernel cpumask format: comma-separated 32-bit hex words,
+// most significant word first, e.g. "00000000,0000ffff".
+func (b bitmask) mask() string {
+	b = b.trim()
+
+	var words []string
+	for _, w := range b {
+		words = append(words, fmt.Sprintf("%08x", uint32(w)), fmt.Sprintf("%08x", uint32(w>>32)))
+	}
+	// The last 64-bit word could have empty upper half.
+	if len(words) > 0 && words[len(words)-1] == "00000000" {
+		words = words[:len(words)-1]
+	}
+	if len(words) == 0 {
+		return "00000000"
+	}
+
+	for i, j := 0, len(words)-1; i < j; i, j = i+1, j-1 {
+		words[i], words[j] = words[j], words[i]
+	}
+	return strings.Join(words, ",")
+}
+
+// list formats bitmask in kernel list format, e.g. "0-3,8,10-11".
+func (b bitmask) list() string {
+	ids := b.ids()
+
+	var sb strings.Builder
+	for i := 0; i < len(ids); i++ {
+		first := ids[i]
+		for i+1 < len(ids) && ids[i+1] == ids[i]+1 {
+			i++
+		}
+
+		if sb.Len() > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(strconv.Itoa(first))
+		if ids[i] != first {
+			sb.WriteByte('-')
+			sb.WriteString(strconv.Itoa(ids[i]))
+		}
+	}
+
+	return sb.String()
+}
+
+// parseList parses kernel list format, e.g. "0-3,8,10-11".
+func parseList(s string) ([]int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+
+	var ids []int
+	for _, token := range strings.Split(s, ",") {
+		bounds := strings.Split(token, "-")
+		if len(bounds) > 2 {
+			return nil, fmt.Errorf("invalid format: %q", token)
+		}
+
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("convert first %q: %w", bounds[0], err)
+		}
+
+		last := first
+		if len(bounds) == 2 {
+			last, err = strconv.Atoi(bounds[1])
+			if err != nil {
+				return nil, fmt.Errorf("convert last %q: %w", bounds[1], err)
+			}
+		}
+
+		if first < 0 || last < first {
+			return nil, fmt.Errorf("invalid range: %q", token)
+		}
+
+		for i := first; i <= last; i++ {
+			ids = append(ids, i)
+		}
+	}
+
+	return ids, nil
+}
+
+// parseMask parses kernel cpumask format: comma-separated 32-bit hex words,
+// most significant word first, e.g. "00000000,0000ffff".
+func parseMask(s string) (bitmask, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+
+	words := strings.Split(s, ",")
+
+	var b bitmask
+	for i := range words {
+		word := words[len(words)-1-i]
+		v, err := strconv.ParseUint(word, 16, 32)
+		if err != nil {
+			return nil, fmt.Errorf("convert word %q: %w", word, err)
+		}
+
+		for bit := 0; v != 0; bit++ {
+			if v&1 != 0 {
+				b.set(i*32 + bit)
+			}
+			v >>= 1
+		}
+	}
+
+	return b, nil
+}
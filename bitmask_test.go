@@ -0,0 +1,83 @@
+package numa
+
+import (
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func seq(first, last int) []int {
+	var ids []int
+	for i := first; i <= last; i++ {
+		ids = append(ids, i)
+	}
+	return ids
+}
+
+func TestParseList(t *testing.T) {
+	tests := []struct {
+		list    string
+		want    []int
+		wantErr bool
+	}{
+		{list: "0-15,32-47", want: append(seq(0, 15), seq(32, 47)...)},
+		{list: "0,2,4-7", want: []int{0, 2, 4, 5, 6, 7}},
+		{list: "", want: nil},
+		{list: "\n", want: nil},
+		{list: "0-3\n", want: []int{0, 1, 2, 3}},
+		{list: "5", want: []int{5}},
+		{list: "a", wantErr: true},
+		{list: "1-", wantErr: true},
+		{list: "-1", wantErr: true},
+		{list: "3-1", wantErr: true},
+		{list: "1-2-3", wantErr: true},
+		{list: "1,,2", wantErr: true},
+		{list: "0-3,", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseList(tt.list)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseList(%q) = %v, want error", tt.list, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseList(%q): %v", tt.list, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("parseList(%q) = %v, want %v", tt.list, got, tt.want)
+		}
+	}
+}
+
+func TestListRoundTrip(t *testing.T) {
+	for _, list := range []string{"0-15,32-47", "0,2,4-7", "", "1,3,5"} {
+		s, err := ParseCPUSet(list)
+		if err != nil {
+			t.Fatalf("ParseCPUSet(%q): %v", list, err)
+		}
+		if got := s.String(); got != list {
+			t.Errorf("ParseCPUSet(%q).String() = %q", list, got)
+		}
+	}
+}
+
+func TestParseCpuList(t *testing.T) {
+	fsys := fstest.MapFS{
+		"cpu":       {Data: []byte("0-1,4\n")},
+		"memonly":   {Data: []byte("\n")},
+		"malformed": {Data: []byte("0-x\n")},
+	}
+
+	if got, err := parseCpuList(fsys, "cpu"); err != nil || !slices.Equal(got, []int{0, 1, 4}) {
+		t.Errorf("parseCpuList(cpu) = %v, %v", got, err)
+	}
+	if got, err := parseCpuList(fsys, "memonly"); err != nil || len(got) != 0 {
+		t.Errorf("parseCpuList(memonly) = %v, %v", got, err)
+	}
+	if _, err := parseCpuList(fsys, "malformed"); err == nil {
+		t.Error("parseCpuList(malformed) returned no error")
+	}
+}
@@ -0,0 +1,83 @@
+package numa
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// NodeForBlockDevice returns NUMA node of block device, e.g. "nvme0n1", "sda1"
+// or "/dev/dm-0". Partitions resolve to their disk, NVMe multipath namespaces
+// and device-mapper devices resolve to the first path with known locality.
+// It returns -1 if firmware doesn't report device locality.
+func NodeForBlockDevice(dev string, opts ...Option) (int, error) {
+	return nodeForBlockDevice(newOptions(opts), strings.TrimPrefix(dev, "/dev/"), 0)
+}
+
+func nodeForBlockDevice(o options, name string, depth int) (int, error) {
+	if depth > 8 {
+		return -1, fmt.Errorf("block device %s: too many stacked devices", name)
+	}
+
+	path, err := filepath.EvalSymlinks(o.sysPath("class/block", name))
+	if err != nil {
+		return -1, err
+	}
+
+	// Partition directory is nested in its disk directory.
+	if _, err := os.Stat(filepath.Join(path, "partition")); err == nil {
+		path = filepath.Dir(path)
+	}
+
+	// NVMe native multipath head has no device, its paths are listed in multipath.
+	for _, stacked := range []string{"multipath", "slaves"} {
+		dir, err := os.ReadDir(filepath.Join(path, stacked))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return -1, err
+		}
+
+		for _, i := range dir {
+			node, err := nodeForBlockDevice(o, i.Name(), depth+1)
+			if err != nil {
+				return -1, err
+			}
+			if node >= 0 {
+				return node, nil
+			}
+		}
+
+		if len(dir) > 0 {
+			return -1, nil
+		}
+	}
+
+	device, err := filepath.EvalSymlinks(filepath.Join(path, "device"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return -1, nil
+		}
+		return -1, err
+	}
+
+	return nodeForDevicePath(o, device)
+}
+
+// nodeForDevicePath walks sysfs device path up to the first parent reporting numa_node.
+func nodeForDevicePath(o options, path string) (int, error) {
+	devices := o.sysPath("devices") + string(filepath.Separator)
+	for ; strings.HasPrefix(path, devices); path = filepath.Dir(path) {
+		node, err := readInt(os.DirFS(path), "numa_node")
+		if err == nil {
+			return node, nil
+		}
+		if !os.IsNotExist(err) {
+			return -1, err
+		}
+	}
+
+	return -1, nil
+}
@@ -0,0 +1,91 @@
+package numa
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BuddyInfo represent free memory blocks of NUMA node zone.
+type BuddyInfo struct {
+	Node int
+	Zone string
+	// Free is number of free blocks indexed by order,
+	// block of order N is 2^N pages.
+	Free []uint64
+}
+
+// GetBuddyInfo returns free memory blocks per order of every node zone.
+// Malformed lines are handled according to WithParseMode.
+func GetBuddyInfo(opts ...Option) ([]BuddyInfo, error) {
+	o := newOptions(opts)
+
+	f, err := o.proc.Open("buddyinfo")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var infos []BuddyInfo
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// Node 0, zone   Normal     23    890   1874    274
+		line := scanner.Text()
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 4 || fields[0] != "Node" || fields[2] != "zone" {
+			if err := o.malformedLine("buddyinfo", line); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		node, err := strconv.Atoi(strings.TrimSuffix(fields[1], ","))
+		if err != nil {
+			if err := o.malformedValue("buddyinfo", fmt.Errorf("convert node %q: %w", fields[1], err)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		free, err := parseUint64s(fields[4:])
+		if err != nil {
+			if err := o.malformedValue("buddyinfo", err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		infos = append(infos, BuddyInfo{Node: node, Zone: fields[3], Free: free})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return infos, nil
+}
+
+// FreePagesAtLeast returns number of free pages in blocks of at least given order.
+func (b BuddyInfo) FreePagesAtLeast(order int) uint64 {
+	var pages uint64
+	for o := order; o < len(b.Free); o++ {
+		pages += b.Free[o] << uint(o)
+	}
+	return pages
+}
+
+func parseUint64s(fields []string) ([]uint64, error) {
+	values := make([]uint64, 0, len(fields))
+	for _, field := range fields {
+		v, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("convert %q: %w", field, err)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
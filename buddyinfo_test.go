@@ -0,0 +1,67 @@
+package numa
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestGetBuddyInfoParseMode(t *testing.T) {
+	fsys := fstest.MapFS{"buddyinfo": {Data: []byte("" +
+		"Node 0, zone      DMA      1      1      0\n" +
+		"garbage\n" +
+		"Node 0, zone   Normal     23    x90   1874\n" +
+		"Node 1, zone   Normal     10     20     30\n")}}
+
+	tests := []struct {
+		mode    ParseMode
+		wantErr bool
+		infos   int
+	}{
+		{ParseDefault, true, 0},
+		{ParseStrict, true, 0},
+		{ParseLenient, false, 2},
+	}
+	for _, tt := range tests {
+		infos, err := GetBuddyInfo(WithProcFS(fsys), WithParseMode(tt.mode))
+		if (err != nil) != tt.wantErr || len(infos) != tt.infos {
+			t.Errorf("mode %d: GetBuddyInfo() = %d infos, %v, want %d infos, error %t", tt.mode, len(infos), err, tt.infos, tt.wantErr)
+		}
+	}
+
+	// Malformed lines are skipped by default, only values fail.
+	fsys["buddyinfo"].Data = []byte("garbage\nNode 1, zone   Normal     10     20     30\n")
+	if infos, err := GetBuddyInfo(WithProcFS(fsys)); err != nil || len(infos) != 1 {
+		t.Errorf("GetBuddyInfo() = %v, %v, want 1 info", infos, err)
+	}
+	if _, err := GetBuddyInfo(WithProcFS(fsys), WithParseMode(ParseStrict)); err == nil {
+		t.Error("GetBuddyInfo() in strict mode accepted malformed line")
+	}
+}
+
+func TestGetPageTypeInfoParseMode(t *testing.T) {
+	fsys := fstest.MapFS{"pagetypeinfo": {Data: []byte("" +
+		"Page block order: 9\n" +
+		"Pages per block:  512\n" +
+		"\n" +
+		"Free pages count per migrate type at order       0      1      2\n" +
+		"Node    0, zone   Normal, type    Unmovable      1      x      3\n" +
+		"Node    0, zone   Normal, type      Movable      4      5      6\n" +
+		"\n" +
+		"Number of blocks type     Unmovable      Movable  Reclaimable\n" +
+		"Node 0, zone   Normal           54          570           16\n")}}
+
+	if _, err := GetPageTypeInfo(WithProcFS(fsys)); err == nil {
+		t.Error("GetPageTypeInfo() accepted malformed value")
+	}
+
+	info, err := GetPageTypeInfo(WithProcFS(fsys), WithParseMode(ParseLenient))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.PageBlockOrder != 9 || info.PagesPerBlock != 512 || len(info.Free) != 1 || len(info.Blocks) != 1 {
+		t.Errorf("GetPageTypeInfo() = %+v", info)
+	}
+	if info.Free[0].Type != "Movable" || info.Blocks[0].Blocks["Movable"] != 570 {
+		t.Errorf("GetPageTypeInfo() = %+v", info)
+	}
+}
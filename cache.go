@@ -0,0 +1,124 @@
+package numa
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Cache serves recently read nodes. Nodes older than TTL are returned
+// while being refreshed in background. It's safe for concurrent use.
+type Cache struct {
+	ttl  time.Duration
+	opts []Option
+
+	mu         sync.Mutex
+	nodes      []Node
+	updated    time.Time
+	valid      bool
+	refreshing bool
+	// generation is incremented by Invalidate to discard refreshes started before it.
+	generation uint64
+	// cpuNodes is built from nodes on the first NodeForCPU call after nodes are stored.
+	cpuNodes map[int]int
+}
+
+// NewCache returns Cache reading nodes with GetNodes options,
+// nodes are read on the first call of Nodes.
+func NewCache(ttl time.Duration, opts ...Option) *Cache {
+	return &Cache{ttl: ttl, opts: opts}
+}
+
+// Nodes returns cached nodes. Nodes are read synchronously if cache is empty
+// or invalidated, stale nodes are returned and refreshed in background.
+// Returned nodes are shared between callers and must not be modified.
+func (c *Cache) Nodes() ([]Node, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.valid {
+		nodes, err := GetNodes(c.opts...)
+		if err != nil {
+			// Partial nodes aren't cached.
+			return nodes, err
+		}
+		c.store(nodes)
+		return nodes, nil
+	}
+
+	if time.Since(c.updated) > c.ttl && !c.refreshing {
+		c.refreshing = true
+		go c.refresh(c.generation)
+	}
+
+	return c.nodes, nil
+}
+
+// NodeForCPU returns node of CPU from cached nodes. Lookup is O(1) unless
+// nodes are read or refreshed since the previous call.
+func (c *Cache) NodeForCPU(cpu int) (int, error) {
+	nodes, err := c.Nodes()
+	if err != nil {
+		return -1, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Nodes could be refreshed or invalidated after c.Nodes returned.
+	if c.valid {
+		nodes = c.nodes
+	}
+
+	cpuNodes := c.cpuNodes
+	if cpuNodes == nil {
+		cpuNodes = make(map[int]int)
+		for _, n := range nodes {
+			for _, id := range n.CPU {
+				cpuNodes[id] = n.ID
+			}
+		}
+		if c.valid {
+			c.cpuNodes = cpuNodes
+		}
+	}
+
+	node, ok := cpuNodes[cpu]
+	if !ok {
+		return -1, fmt.Errorf("cpu%d: %w", cpu, ErrNodeNotFound)
+	}
+
+	return node, nil
+}
+
+// Invalidate drops cached nodes, the next Nodes call reads them synchronously.
+func (c *Cache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.valid = false
+	c.nodes = nil
+	c.cpuNodes = nil
+	c.generation++
+}
+
+// refresh reads nodes in background, stale nodes are kept if reading fails.
+func (c *Cache) refresh(generation uint64) {
+	nodes, err := GetNodes(c.opts...)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.refreshing = false
+	if err != nil || generation != c.generation {
+		return
+	}
+	c.store(nodes)
+}
+
+func (c *Cache) store(nodes []Node) {
+	c.nodes = nodes
+	c.cpuNodes = nil
+	c.updated = time.Now()
+	c.valid = true
+}
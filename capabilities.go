@@ -0,0 +1,117 @@
+package numa
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+)
+
+// Capability represent availability of information source.
+type Capability struct {
+	Available bool `json:"available" yaml:"available"`
+	// Reason is error which made source unavailable, e.g. permission denied.
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// Capabilities report which sources the current process can read with its
+// permissions and mount namespaces, e.g. in container without host sysfs or
+// without CAP_SYS_PTRACE. Tools could disable features deliberately instead
+// of failing in the middle of collection.
+type Capabilities struct {
+	// NodeSysfs is node directories of sysfs, needed to read nodes on Linux.
+	NodeSysfs Capability `json:"node_sysfs" yaml:"node_sysfs"`
+	// NodeMemInfo, NodeNumaStat and NodeVMStat are files of the first node.
+	NodeMemInfo  Capability `json:"node_meminfo" yaml:"node_meminfo"`
+	NodeNumaStat Capability `json:"node_numastat" yaml:"node_numastat"`
+	NodeVMStat   Capability `json:"node_vmstat" yaml:"node_vmstat"`
+	// ZoneInfo is /proc/zoneinfo, without it available memory is estimated
+	// without watermarks.
+	ZoneInfo Capability `json:"zoneinfo" yaml:"zoneinfo"`
+	// MemoryTiers is memory tiering sysfs of kernels 6.1 and later.
+	MemoryTiers Capability `json:"memory_tiers" yaml:"memory_tiers"`
+	// CgroupCpuset is cpuset cgroup of the current process used by WithCgroupFiltering.
+	CgroupCpuset Capability `json:"cgroup_cpuset" yaml:"cgroup_cpuset"`
+	// SelfNumaMaps is numa_maps of the current process.
+	SelfNumaMaps Capability `json:"self_numa_maps" yaml:"self_numa_maps"`
+	// OtherNumaMaps is numa_maps of other processes probed with pid 1,
+	// which requires ptrace access, e.g. root or CAP_SYS_PTRACE.
+	OtherNumaMaps Capability `json:"other_numa_maps" yaml:"other_numa_maps"`
+}
+
+// GetCapabilities probes sources read by GetNodes, ParseNumaMaps and other
+// readers with opts. Files are opened and their first byte is read, as
+// permissions of procfs files are checked on read too.
+func GetCapabilities(opts ...Option) Capabilities {
+	o := newOptions(opts)
+
+	var c Capabilities
+
+	ids, err := sysfsNodeIDs(o)
+	if err == nil && len(ids) == 0 {
+		err = fmt.Errorf("%w: no node directories", ErrNoNUMA)
+	}
+	c.NodeSysfs = newCapability(err)
+
+	if c.NodeSysfs.Available {
+		nodePath := path.Join("devices/system/node", fmt.Sprintf("node%d", ids[0]))
+		c.NodeMemInfo = newCapability(probeFile(o.sys, path.Join(nodePath, "meminfo")))
+		c.NodeNumaStat = newCapability(probeFile(o.sys, path.Join(nodePath, "numastat")))
+		c.NodeVMStat = newCapability(probeFile(o.sys, path.Join(nodePath, "vmstat")))
+	} else {
+		c.NodeMemInfo, c.NodeNumaStat, c.NodeVMStat = c.NodeSysfs, c.NodeSysfs, c.NodeSysfs
+	}
+
+	c.ZoneInfo = newCapability(probeFile(o.proc, "zoneinfo"))
+
+	_, err = fs.ReadDir(o.sys, "devices/virtual/memory_tiering")
+	c.MemoryTiers = newCapability(err)
+
+	_, err = readCgroupCpuset()
+	c.CgroupCpuset = newCapability(err)
+
+	c.SelfNumaMaps = newCapability(probeOSFile(procPath(0, "numa_maps")))
+	if os.Getpid() == 1 {
+		c.OtherNumaMaps = newCapability(errors.New("current process is pid 1"))
+	} else {
+		c.OtherNumaMaps = newCapability(probeOSFile(procPath(1, "numa_maps")))
+	}
+
+	return c
+}
+
+func newCapability(err error) Capability {
+	if err != nil {
+		return Capability{Reason: err.Error()}
+	}
+	return Capability{Available: true}
+}
+
+func probeFile(fsys fs.FS, name string) error {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return probeRead(f)
+}
+
+func probeOSFile(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return probeRead(f)
+}
+
+func probeRead(r io.Reader) error {
+	if _, err := r.Read(make([]byte, 1)); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
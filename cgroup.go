@@ -0,0 +1,343 @@
+package numa
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var errNoCgroup = errors.New("cgroup not found")
+
+// cgroup represent line of /proc/<pid>/cgroup, e.g. "3:cpuset:/docker/abc".
+type cgroup struct {
+	controllers []string
+	path        string
+}
+
+// cgroupMount represent cgroup filesystem mount from /proc/self/mountinfo.
+type cgroupMount struct {
+	root       string
+	mountPoint string
+	fsType     string
+	options    []string
+}
+
+// EffectiveCPUs returns CPUs of the current process cpuset cgroup,
+// cgroup v2 is used if it has cpuset controller, cgroup v1 otherwise.
+// It returns CPUs allowed by kernel if cpuset cgroup isn't available.
+func EffectiveCPUs() (CPUSet, error) {
+	list, err := readCpuset("cpuset.cpus.effective", "cpuset.effective_cpus", "cpuset.cpus")
+	if errors.Is(err, errNoCgroup) {
+		return ProcessAllowedCPUs(0)
+	}
+	if err != nil {
+		return CPUSet{}, err
+	}
+
+	cpus, err := ParseCPUSet(list)
+	if err != nil {
+		return CPUSet{}, fmt.Errorf("parse cpuset cpus: %w", err)
+	}
+
+	return cpus, nil
+}
+
+// EffectiveNodes returns memory nodes of the current process cpuset cgroup,
+// cgroup v2 is used if it has cpuset controller, cgroup v1 otherwise.
+// It returns nodes allowed by kernel if cpuset cgroup isn't available.
+func EffectiveNodes() (NodeSet, error) {
+	list, err := readCpuset("cpuset.mems.effective", "cpuset.effective_mems", "cpuset.mems")
+	if errors.Is(err, errNoCgroup) {
+		return ProcessAllowedNodes(0)
+	}
+	if err != nil {
+		return NodeSet{}, err
+	}
+
+	nodes, err := ParseNodeSet(list)
+	if err != nil {
+		return NodeSet{}, fmt.Errorf("parse cpuset mems: %w", err)
+	}
+
+	return nodes, nil
+}
+
+// filterByCgroup removes CPUs and nodes unavailable in the current process
+// cpuset cgroup and clears memory of nodes outside of cpuset mems.
+func filterByCgroup(nodes []Node) ([]Node, error) {
+	cpuset, err := readCgroupCpuset()
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []Node
+	for _, n := range nodes {
+		if n, ok := cpuset.filter(n); ok {
+			filtered = append(filtered, n)
+		}
+	}
+
+	return filtered, nil
+}
+
+// cgroupCpuset represent CPUs and memory nodes of the current process cpuset cgroup.
+type cgroupCpuset struct {
+	cpus CPUSet
+	mems NodeSet
+}
+
+func readCgroupCpuset() (cgroupCpuset, error) {
+	cpus, err := EffectiveCPUs()
+	if err != nil {
+		return cgroupCpuset{}, fmt.Errorf("read effective cpus: %w", err)
+	}
+
+	mems, err := EffectiveNodes()
+	if err != nil {
+		return cgroupCpuset{}, fmt.Errorf("read effective nodes: %w", err)
+	}
+
+	return cgroupCpuset{cpus: cpus, mems: mems}, nil
+}
+
+// filter removes CPUs outside of cpuset and clears memory of node outside of
+// cpuset mems. It returns false if node has neither CPUs nor memory in cpuset.
+func (c cgroupCpuset) filter(n Node) (Node, bool) {
+	var nodeCPUs []int
+	for _, cpu := range n.CPU {
+		if c.cpus.Contains(cpu) {
+			nodeCPUs = append(nodeCPUs, cpu)
+		}
+	}
+
+	hasMemory := c.mems.Contains(n.ID)
+	if len(nodeCPUs) == 0 && !hasMemory {
+		return Node{}, false
+	}
+
+	n.CPU = nodeCPUs
+	n.HasCPU = len(nodeCPUs) > 0
+	if !hasMemory {
+		n.clearMemory()
+	}
+
+	return n, true
+}
+
+// clearMemory resets fields describing memory of node, e.g. of node
+// outside of cpuset mems, so the node appears memory-less.
+func (n *Node) clearMemory() {
+	n.MemTotal, n.MemFree, n.MemAvailable = 0, 0, 0
+	n.MemInfo = MemInfo{}
+	n.MemApproximate = false
+	n.Stats = NodeStats{}
+	n.Hugepages = nil
+	n.Zones = nil
+	n.MemorySideCaches = nil
+	n.Access = nil
+	n.MemoryTier = -1
+	n.HasMemory = false
+	n.HasNormalMemory = false
+}
+
+// readCpuset returns content of cgroup v2 file or the first existing
+// cgroup v1 file of the cpuset cgroup.
+func readCpuset(v2Name string, v1Names ...string) (string, error) {
+	list, err := readCgroupV2File(0, v2Name)
+	if !errors.Is(err, errNoCgroup) {
+		return list, err
+	}
+
+	dir, err := cgroupV1Dir(0, "cpuset")
+	if err != nil {
+		return "", err
+	}
+
+	for _, name := range v1Names {
+		f, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return "", err
+		}
+		return strings.TrimSpace(string(f)), nil
+	}
+
+	return "", fmt.Errorf("%v in %s: %w", v1Names, dir, errNoCgroup)
+}
+
+// readCgroupV2File returns content of file in process cgroup v2 directory.
+// Controller files are missing in cgroups without the controller enabled,
+// then the closest ancestor having the file is used.
+func readCgroupV2File(pid int, name string) (string, error) {
+	dir, mountPoint, err := cgroupV2Dir(pid)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		f, err := os.ReadFile(filepath.Join(dir, name))
+		if err == nil {
+			return strings.TrimSpace(string(f)), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+
+		if dir == mountPoint || !strings.HasPrefix(dir, mountPoint) {
+			return "", fmt.Errorf("%s: %w", name, errNoCgroup)
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// cgroupV2Dir returns directory of process cgroup in unified hierarchy and its mount point.
+func cgroupV2Dir(pid int) (string, string, error) {
+	cgroups, err := readCgroups(pid)
+	if err != nil {
+		return "", "", err
+	}
+
+	mounts, err := readCgroupMounts()
+	if err != nil {
+		return "", "", err
+	}
+
+	for _, c := range cgroups {
+		// 0::/system.slice/app.service
+		if len(c.controllers) != 0 {
+			continue
+		}
+
+		for _, m := range mounts {
+			if m.fsType == "cgroup2" {
+				return cgroupDir(m, c.path), m.mountPoint, nil
+			}
+		}
+	}
+
+	return "", "", fmt.Errorf("unified hierarchy: %w", errNoCgroup)
+}
+
+// cgroupV1Dir returns directory of process cgroup in cgroup v1 controller hierarchy.
+func cgroupV1Dir(pid int, controller string) (string, error) {
+	cgroups, err := readCgroups(pid)
+	if err != nil {
+		return "", err
+	}
+
+	mounts, err := readCgroupMounts()
+	if err != nil {
+		return "", err
+	}
+
+	for _, c := range cgroups {
+		if !contains(c.controllers, controller) {
+			continue
+		}
+
+		for _, m := range mounts {
+			if m.fsType == "cgroup" && contains(m.options, controller) {
+				return cgroupDir(m, c.path), nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("%s controller: %w", controller, errNoCgroup)
+}
+
+// cgroupDir returns directory of cgroup path in mounted hierarchy.
+func cgroupDir(m cgroupMount, path string) string {
+	// Mount root is a cgroup directory when hierarchy is bind mounted into container.
+	if m.root != "/" && strings.HasPrefix(path, m.root) {
+		path = strings.TrimPrefix(path, m.root)
+	}
+	return filepath.Join(m.mountPoint, path)
+}
+
+func readCgroups(pid int) ([]cgroup, error) {
+	f, err := os.Open(procPath(pid, "cgroup"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var cgroups []cgroup
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// 3:cpuset:/docker/abc
+		tokens := strings.SplitN(scanner.Text(), ":", 3)
+		if len(tokens) != 3 {
+			continue
+		}
+
+		var controllers []string
+		if tokens[1] != "" {
+			controllers = strings.Split(tokens[1], ",")
+		}
+		cgroups = append(cgroups, cgroup{controllers: controllers, path: tokens[2]})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return cgroups, nil
+}
+
+func readCgroupMounts() ([]cgroupMount, error) {
+	f, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var mounts []cgroupMount
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// 35 32 0:31 / /sys/fs/cgroup/cpuset rw,relatime - cgroup cgroup rw,cpuset
+		fields := strings.Fields(scanner.Text())
+
+		separator := -1
+		for i, field := range fields {
+			if field == "-" {
+				separator = i
+				break
+			}
+		}
+		if separator < 5 || len(fields) < separator+4 {
+			continue
+		}
+
+		fsType := fields[separator+1]
+		if fsType != "cgroup" && fsType != "cgroup2" {
+			continue
+		}
+
+		mounts = append(mounts, cgroupMount{
+			root:       fields[3],
+			mountPoint: fields[4],
+			fsType:     fsType,
+			options:    strings.Split(fields[separator+3], ","),
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return mounts, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
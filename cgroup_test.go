@@ -0,0 +1,59 @@
+package numa
+
+import "testing"
+
+func TestCgroupCpusetFilter(t *testing.T) {
+	n := Node{
+		ID:              1,
+		CPU:             []int{2, 3},
+		MemTotal:        4 << 30,
+		MemFree:         2 << 30,
+		MemAvailable:    3 << 30,
+		MemInfo:         MemInfo{MemTotal: 4 << 30, Raw: map[string]uint64{"MemTotal": 4 << 30}},
+		Stats:           NodeStats{NumaHit: 1},
+		Hugepages:       map[uint64]Hugepages{2 << 20: {Total: 1}},
+		Zones:           []Zone{{Node: 1, Name: "Normal"}},
+		MemoryTier:      4,
+		HasCPU:          true,
+		HasMemory:       true,
+		HasNormalMemory: true,
+	}
+
+	tests := []struct {
+		name      string
+		cpuset    cgroupCpuset
+		ok        bool
+		cpus      int
+		hasMemory bool
+	}{
+		{"all", cgroupCpuset{cpus: NewCPUSet(2, 3), mems: NewNodeSet(1)}, true, 2, true},
+		{"cpus only", cgroupCpuset{cpus: NewCPUSet(3), mems: NewNodeSet(0)}, true, 1, false},
+		{"memory only", cgroupCpuset{cpus: NewCPUSet(0), mems: NewNodeSet(1)}, true, 0, true},
+		{"none", cgroupCpuset{cpus: NewCPUSet(0), mems: NewNodeSet(0)}, false, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.cpuset.filter(n)
+			if ok != tt.ok {
+				t.Fatalf("filter() ok = %t, want %t", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if len(got.CPU) != tt.cpus || got.HasCPU != (tt.cpus > 0) {
+				t.Errorf("CPU = %v, HasCPU = %t, want %d CPUs", got.CPU, got.HasCPU, tt.cpus)
+			}
+			if tt.hasMemory {
+				if got.MemTotal != n.MemTotal || !got.HasMemory {
+					t.Errorf("memory of node in cpuset mems is cleared: %+v", got)
+				}
+				return
+			}
+			if got.MemTotal != 0 || got.MemFree != 0 || got.MemAvailable != 0 || got.MemInfo.MemTotal != 0 ||
+				got.MemInfo.Raw != nil || got.Stats != (NodeStats{}) || got.Hugepages != nil || got.Zones != nil ||
+				got.MemoryTier != -1 || got.HasMemory || got.HasNormalMemory {
+				t.Errorf("memory of node outside of cpuset mems isn't cleared: %+v", got)
+			}
+		})
+	}
+}
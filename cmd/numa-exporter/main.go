@@ -0,0 +1,96 @@
+// Command numa-exporter serves NUMA nodes metrics for Prometheus.
+//
+// Nodes are read on every scrape. Per process memory from numa_maps
+// is exported if -process-metrics is set.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/oneumyvakin/numa"
+	numaprometheus "github.com/oneumyvakin/numa/prometheus"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func main() {
+	listen := flag.String("listen", ":9877", "address to listen on")
+	path := flag.String("path", "/metrics", "path to serve metrics on")
+	cgroup := flag.Bool("cgroup", false, "report only CPUs and nodes of the exporter cpuset cgroup")
+	processMetrics := flag.Bool("process-metrics", false, "export memory of every process per node from numa_maps")
+	processTimeout := flag.Duration("process-timeout", 10*time.Second, "timeout of reading numa_maps of all processes")
+	flag.Parse()
+
+	var opts []numa.Option
+	if *cgroup {
+		opts = append(opts, numa.WithCgroupFiltering())
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(numaprometheus.NewCollector(opts...))
+	if *processMetrics {
+		registry.MustRegister(newProcessCollector(*processTimeout))
+	}
+
+	http.Handle(*path, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+
+	log.Printf("listening on %s", *listen)
+	log.Fatal(http.ListenAndServe(*listen, nil))
+}
+
+// processCollector exports memory of processes per node from numa_maps.
+type processCollector struct {
+	timeout time.Duration
+
+	process *prometheus.Desc
+	total   *prometheus.Desc
+}
+
+func newProcessCollector(timeout time.Duration) *processCollector {
+	return &processCollector{
+		timeout: timeout,
+		process: prometheus.NewDesc(
+			"numa_process_memory_bytes",
+			"Memory of process on NUMA node from numa_maps.",
+			[]string{"pid", "name", "node"}, nil,
+		),
+		total: prometheus.NewDesc(
+			"numa_processes_memory_bytes",
+			"Memory of all processes on NUMA node from numa_maps.",
+			[]string{"node"}, nil,
+		),
+	}
+}
+
+func (c *processCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.process
+	ch <- c.total
+}
+
+func (c *processCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	usage, err := numa.ScanProcesses(ctx, runtime.NumCPU())
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.total, err)
+		return
+	}
+
+	for _, p := range usage.Processes {
+		pid := strconv.Itoa(p.PID)
+		for node, bytes := range p.Nodes {
+			ch <- prometheus.MustNewConstMetric(c.process, prometheus.GaugeValue, float64(bytes), pid, p.Name, strconv.Itoa(node))
+		}
+	}
+
+	for node, bytes := range usage.Nodes {
+		ch <- prometheus.MustNewConstMetric(c.total, prometheus.GaugeValue, float64(bytes), strconv.Itoa(node))
+	}
+}
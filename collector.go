@@ -0,0 +1,159 @@
+package numa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"iter"
+	"path"
+	"sync"
+)
+
+// Collector reads nodes with options given once, e.g. sources with
+// WithSysFS and fields with WithFields. GetNodes, GetNode and Node.Refresh
+// are shortcuts for a Collector with their options. It's safe for concurrent use,
+// concurrent Nodes calls share a single read.
+type Collector struct {
+	o options
+
+	mu sync.Mutex
+	// call is Nodes read in progress.
+	call *nodesCall
+}
+
+// nodesCall represent Nodes read shared by concurrent callers.
+type nodesCall struct {
+	done  chan struct{}
+	nodes []Node
+	err   error
+}
+
+// defaultCollector is shared by GetNodes calls without options.
+var defaultCollector = NewCollector()
+
+// NewCollector returns Collector reading nodes with opts.
+func NewCollector(opts ...Option) *Collector {
+	return &Collector{o: newOptions(opts)}
+}
+
+// Nodes returns all nodes like GetNodes. Calls made while another call reads
+// nodes wait for its result instead of reading sysfs again. Nodes returns
+// context error when ctx is done, shared read isn't stopped by it.
+func (c *Collector) Nodes(ctx context.Context) ([]Node, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	call := c.call
+	if call == nil {
+		call = &nodesCall{done: make(chan struct{})}
+		c.call = call
+		go func() {
+			call.nodes, call.err = c.readNodes(context.WithoutCancel(ctx))
+
+			c.mu.Lock()
+			c.call = nil
+			c.mu.Unlock()
+			close(call.done)
+		}()
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	// Every caller gets its own copy as callers could modify nodes.
+	var nodes []Node
+	for _, n := range call.nodes {
+		nodes = append(nodes, n.clone())
+	}
+
+	return nodes, call.err
+}
+
+func (c *Collector) readNodes(ctx context.Context) ([]Node, error) {
+	return collectNodes(ctx, c.o)
+}
+
+// collectNodes reads all nodes, errors of single nodes are joined.
+func collectNodes(ctx context.Context, o options) ([]Node, error) {
+	var nodes []Node
+	var errs []error
+	for n, err := range nodesSeq(ctx, o) {
+		var nodeErr *NodeError
+		if errors.As(err, &nodeErr) {
+			errs = append(errs, err)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		nodes = append(nodes, n)
+	}
+
+	return nodes, errors.Join(errs...)
+}
+
+// All returns iterator yielding nodes as they are read, see Nodes function.
+func (c *Collector) All(ctx context.Context) iter.Seq2[Node, error] {
+	return nodesSeq(ctx, c.o)
+}
+
+// Node returns single node like GetNode.
+func (c *Collector) Node(id int) (Node, error) {
+	if nodes, ok, err := providerNodes(context.Background(), c.o); ok {
+		for _, n := range nodes {
+			if n.ID == id {
+				return n, nil
+			}
+		}
+		if err != nil {
+			return Node{}, err
+		}
+		return Node{}, &NodeError{Node: id, Err: ErrNodeNotFound}
+	}
+
+	if _, err := fs.Stat(c.o.sys, path.Join("devices/system/node", fmt.Sprintf("node%d", id))); err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return Node{}, &NodeError{Node: id, Err: err}
+		}
+		if _, err := fs.Stat(c.o.sys, "devices/system/node"); err != nil {
+			return Node{}, noNUMAErr(err)
+		}
+		return Node{}, &NodeError{Node: id, Err: fmt.Errorf("%w: %w", ErrNodeNotFound, err)}
+	}
+
+	shared, err := readSharedInfo(c.o)
+	if err != nil {
+		return Node{}, err
+	}
+
+	n, err := readNode(c.o, shared, id)
+	if err != nil {
+		return Node{}, &NodeError{Node: id, Err: err}
+	}
+
+	if c.o.cgroupFiltering {
+		filtered, err := filterByCgroup([]Node{n})
+		if err != nil {
+			return Node{}, err
+		}
+		if len(filtered) == 0 {
+			return Node{}, &NodeError{Node: id, Err: fmt.Errorf("%w: not available in cpuset cgroup", ErrNodeNotFound)}
+		}
+		n = filtered[0]
+	}
+
+	return n, nil
+}
+
+// Refresh re-reads volatile fields of n like Node.Refresh.
+func (c *Collector) Refresh(n *Node) error {
+	return refreshNode(c.o, n)
+}
@@ -0,0 +1,48 @@
+package numa
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Compact triggers memory compaction of the node. It requires root.
+func (n Node) Compact(opts ...Option) error {
+	return CompactNode(n.ID, opts...)
+}
+
+// CompactNode triggers memory compaction of NUMA node. It requires root.
+// Sysfs is written at WithSysRoot root, WithSysFS isn't writable.
+func CompactNode(nodeID int, opts ...Option) error {
+	path := newOptions(opts).sysPath("devices/system/node", fmt.Sprintf("node%d", nodeID), "compact")
+	if err := writeTrigger(path); err != nil {
+		return fmt.Errorf("compact node %d: %w", nodeID, err)
+	}
+	return nil
+}
+
+// CompactAll triggers memory compaction of all nodes. It requires root.
+// Procfs is written at WithProcRoot root, WithProcFS isn't writable.
+func CompactAll(opts ...Option) error {
+	if err := writeTrigger(filepath.Join(newOptions(opts).procRoot, "sys/vm/compact_memory")); err != nil {
+		return fmt.Errorf("compact memory: %w", err)
+	}
+	return nil
+}
+
+func writeTrigger(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		if os.IsPermission(err) {
+			return fmt.Errorf("%w: requires CAP_SYS_ADMIN", err)
+		}
+		return err
+	}
+
+	if _, err := f.WriteString("1"); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,36 @@
+package numa
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompactRoots(t *testing.T) {
+	sys, proc := t.TempDir(), t.TempDir()
+	nodeCompact := filepath.Join(sys, "devices/system/node/node1/compact")
+	compactMemory := filepath.Join(proc, "sys/vm/compact_memory")
+	for _, name := range []string{nodeCompact, compactMemory} {
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		writeFile(t, name, "")
+	}
+
+	if err := CompactNode(1, WithSysRoot(sys)); err != nil {
+		t.Fatal(err)
+	}
+	if err := CompactAll(WithProcRoot(proc)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{nodeCompact, compactMemory} {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != "1" {
+			t.Errorf("%s = %q, want %q", name, data, "1")
+		}
+	}
+}
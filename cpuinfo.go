@@ -0,0 +1,108 @@
+package numa
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// CPUInfo represent CPU topology: socket, core and SMT siblings.
+type CPUInfo struct {
+	ID        int
+	PackageID int
+	CoreID    int
+	// ThreadSiblings are CPU IDs sharing the same physical core, including this CPU.
+	ThreadSiblings []int
+}
+
+// IsPrimaryThread reports whether CPU is the first SMT thread of its core.
+func (c CPUInfo) IsPrimaryThread() bool {
+	return len(c.ThreadSiblings) == 0 || c.ThreadSiblings[0] == c.ID
+}
+
+// GetCPUInfo returns topology of CPU.
+func GetCPUInfo(cpu int, opts ...Option) (CPUInfo, error) {
+	return getCPUInfo(newOptions(opts), cpu)
+}
+
+func getCPUInfo(o options, cpu int) (CPUInfo, error) {
+	topologyPath := path.Join("devices/system/cpu", fmt.Sprintf("cpu%d", cpu), "topology")
+
+	info := CPUInfo{ID: cpu}
+	var err error
+	if info.PackageID, err = readInt(o.sys, path.Join(topologyPath, "physical_package_id")); err != nil {
+		return CPUInfo{}, err
+	}
+	if info.CoreID, err = readInt(o.sys, path.Join(topologyPath, "core_id")); err != nil {
+		return CPUInfo{}, err
+	}
+
+	siblings, err := fs.ReadFile(o.sys, path.Join(topologyPath, "thread_siblings_list"))
+	if err != nil {
+		return CPUInfo{}, err
+	}
+	if info.ThreadSiblings, err = parseList(string(siblings)); err != nil {
+		return CPUInfo{}, fmt.Errorf("parse thread_siblings_list: %w", err)
+	}
+
+	return info, nil
+}
+
+// CPUTopology returns topology of every CPU of the node.
+func (n Node) CPUTopology(opts ...Option) ([]CPUInfo, error) {
+	o := newOptions(opts)
+
+	infos := make([]CPUInfo, 0, len(n.CPU))
+	for _, cpu := range n.CPU {
+		info, err := getCPUInfo(o, cpu)
+		if err != nil {
+			return nil, fmt.Errorf("cpu%d: %w", cpu, err)
+		}
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+// NodeForCPU returns NUMA node of CPU, e.g. returned by CurrentCPU.
+// Only node link of the CPU directory is read from sysfs, nodes of provider
+// or operating system API are looked up like by CPUToNodeMap.
+func NodeForCPU(cpu int, opts ...Option) (int, error) {
+	o := newOptions(opts)
+
+	if nodes, ok, err := providerNodes(context.Background(), o); ok {
+		for _, n := range nodes {
+			if slices.Contains(n.CPU, cpu) {
+				return n.ID, nil
+			}
+		}
+		if err != nil {
+			return -1, err
+		}
+		return -1, fmt.Errorf("cpu%d: %w", cpu, ErrNodeNotFound)
+	}
+
+	dir, err := fs.ReadDir(o.sys, path.Join("devices/system/cpu", fmt.Sprintf("cpu%d", cpu)))
+	if err != nil {
+		return -1, err
+	}
+
+	for _, i := range dir {
+		// node0 -> ../../node/node0
+		if !strings.HasPrefix(i.Name(), "node") {
+			continue
+		}
+
+		node, err := strconv.Atoi(strings.TrimPrefix(i.Name(), "node"))
+		if err != nil {
+			return -1, fmt.Errorf("convert node %q: %w", i.Name(), err)
+		}
+		return node, nil
+	}
+
+	return -1, fmt.Errorf("cpu%d: %w", cpu, ErrNodeNotFound)
+}
@@ -0,0 +1,38 @@
+package numa
+
+import (
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestCPUTopologySysFS(t *testing.T) {
+	sys := fstest.MapFS{}
+	for _, cpu := range []struct {
+		name, pkg, core, siblings string
+	}{
+		{"cpu2", "1", "0", "2-3"},
+		{"cpu3", "1", "0", "2-3"},
+	} {
+		dir := "devices/system/cpu/" + cpu.name + "/topology/"
+		sys[dir+"physical_package_id"] = &fstest.MapFile{Data: []byte(cpu.pkg + "\n")}
+		sys[dir+"core_id"] = &fstest.MapFile{Data: []byte(cpu.core + "\n")}
+		sys[dir+"thread_siblings_list"] = &fstest.MapFile{Data: []byte(cpu.siblings + "\n")}
+	}
+
+	infos, err := Node{ID: 1, CPU: []int{2, 3}}.CPUTopology(WithSysFS(sys))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 2 {
+		t.Fatalf("CPUTopology() returned %d CPUs, want 2", len(infos))
+	}
+	for _, info := range infos {
+		if info.PackageID != 1 || info.CoreID != 0 || !slices.Equal(info.ThreadSiblings, []int{2, 3}) {
+			t.Errorf("CPUTopology() cpu%d = %+v", info.ID, info)
+		}
+	}
+	if !infos[0].IsPrimaryThread() || infos[1].IsPrimaryThread() {
+		t.Errorf("primary threads of %+v are wrong", infos)
+	}
+}
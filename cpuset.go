@@ -0,0 +1,118 @@
+package numa
+
+// CPUSet represent set of CPU IDs.
+type CPUSet struct {
+	mask bitmask
+}
+
+// NewCPUSet returns CPUSet containing given CPU IDs.
+func NewCPUSet(ids ...int) CPUSet {
+	var s CPUSet
+	for _, id := range ids {
+		if id >= 0 {
+			s.mask.set(id)
+		}
+	}
+	return s
+}
+
+// ParseCPUSet parses CPU list in kernel list format, e.g. "0-15,32-47".
+func ParseCPUSet(list string) (CPUSet, error) {
+	ids, err := parseList(list)
+	if err != nil {
+		return CPUSet{}, err
+	}
+	return NewCPUSet(ids...), nil
+}
+
+// Add adds CPU ID to the set. Negative IDs are ignored.
+func (s *CPUSet) Add(id int) {
+	if id < 0 {
+		return
+	}
+	// The mask could be shared with copies of the set.
+	s.mask = s.mask.with(id)
+}
+
+// Contains reports whether CPU ID is in the set.
+func (s CPUSet) Contains(id int) bool {
+	return s.mask.isSet(id)
+}
+
+// Len returns number of CPUs in the set.
+func (s CPUSet) Len() int {
+	return s.mask.count()
+}
+
+// IDs returns CPU IDs in ascending order.
+func (s CPUSet) IDs() []int {
+	return s.mask.ids()
+}
+
+// String returns set in kernel list format.
+func (s CPUSet) String() string {
+	return s.mask.list()
+}
+
+// MarshalText returns set in kernel list format, e.g. "0-1,3".
+func (s CPUSet) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText parses set in kernel list format.
+func (s *CPUSet) UnmarshalText(text []byte) error {
+	parsed, err := ParseCPUSet(string(text))
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
+
+// ParseCPUMask parses CPU mask in kernel cpumask format, e.g. "00000000,0000ffff".
+func ParseCPUMask(mask string) (CPUSet, error) {
+	b, err := parseMask(mask)
+	if err != nil {
+		return CPUSet{}, err
+	}
+	return CPUSet{mask: b}, nil
+}
+
+// Remove removes CPU ID from the set.
+func (s *CPUSet) Remove(id int) {
+	if !s.mask.isSet(id) {
+		return
+	}
+	// The mask could be shared with copies of the set.
+	s.mask = s.mask.difference(NewCPUSet(id).mask)
+}
+
+// Union returns set of CPUs in either set.
+func (s CPUSet) Union(other CPUSet) CPUSet {
+	return CPUSet{mask: s.mask.union(other.mask)}
+}
+
+// Intersect returns set of CPUs in both sets.
+func (s CPUSet) Intersect(other CPUSet) CPUSet {
+	return CPUSet{mask: s.mask.intersect(other.mask)}
+}
+
+// Difference returns set of CPUs in s but not in other.
+func (s CPUSet) Difference(other CPUSet) CPUSet {
+	return CPUSet{mask: s.mask.difference(other.mask)}
+}
+
+// Equal reports whether sets contain the same CPUs.
+func (s CPUSet) Equal(other CPUSet) bool {
+	return s.mask.equal(other.mask)
+}
+
+// Mask returns set in kernel cpumask format, e.g. "00000000,0000ffff".
+func (s CPUSet) Mask() string {
+	return s.mask.mask()
+}
+
+// CPUSet returns CPUs of the node as CPUSet.
+func (n Node) CPUSet() CPUSet {
+	return NewCPUSet(n.CPU...)
+}
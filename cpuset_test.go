@@ -0,0 +1,17 @@
+package numa
+
+import "testing"
+
+func TestCPUSetAddCopy(t *testing.T) {
+	a := NewCPUSet(0, 1)
+	b := a
+	b.Add(2)
+	b.Add(130)
+
+	if got := a.String(); got != "0-1" {
+		t.Errorf("original set = %q, want %q", got, "0-1")
+	}
+	if got := b.String(); got != "0-2,130" {
+		t.Errorf("copy = %q, want %q", got, "0-2,130")
+	}
+}
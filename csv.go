@@ -0,0 +1,62 @@
+package numa
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+var csvHeader = []string{
+	"timestamp", "node", "mem_total", "mem_free", "mem_available",
+	"numa_hit", "numa_miss", "numa_foreign", "interleave_hit", "local_node", "other_node",
+}
+
+// CSVWriter writes node samples as CSV rows, one row per node,
+// with header before the first row. Memory is in bytes.
+type CSVWriter struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+// NewCSVWriter returns CSVWriter writing to w.
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{w: csv.NewWriter(w)}
+}
+
+// Write writes rows of nodes sampled at t and flushes them.
+func (c *CSVWriter) Write(t time.Time, nodes []Node) error {
+	if !c.wroteHeader {
+		if err := c.w.Write(csvHeader); err != nil {
+			return err
+		}
+		c.wroteHeader = true
+	}
+
+	timestamp := t.Format(time.RFC3339)
+	for _, n := range nodes {
+		row := []string{timestamp, strconv.Itoa(n.ID)}
+		for _, v := range []uint64{
+			n.MemTotal, n.MemFree, n.MemAvailable,
+			n.Stats.NumaHit, n.Stats.NumaMiss, n.Stats.NumaForeign,
+			n.Stats.InterleaveHit, n.Stats.LocalNode, n.Stats.OtherNode,
+		} {
+			row = append(row, strconv.FormatUint(v, 10))
+		}
+
+		if err := c.w.Write(row); err != nil {
+			return err
+		}
+	}
+
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// WriteSnapshot writes rows of watcher snapshot, snapshots with error are ignored.
+func (c *CSVWriter) WriteSnapshot(s Snapshot) error {
+	if s.Err != nil {
+		return nil
+	}
+	return c.Write(s.Time, s.Nodes)
+}
@@ -0,0 +1,274 @@
+// Package cxl enumerates CXL memory devices and regions and NUMA nodes
+// they are surfaced as.
+package cxl
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Option configures GetMemdevs and GetRegions.
+type Option func(*options)
+
+type options struct {
+	sysRoot string
+}
+
+func newOptions(opts []Option) options {
+	o := options{sysRoot: "/sys"}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+func (o options) devicesPath() string {
+	return filepath.Join(o.sysRoot, "bus/cxl/devices")
+}
+
+// WithSysRoot makes sysfs to be read from root instead of /sys,
+// e.g. host sysfs mounted into container at /host/sys.
+func WithSysRoot(root string) Option {
+	return func(o *options) {
+		o.sysRoot = root
+	}
+}
+
+// Memdev represent CXL memory device.
+type Memdev struct {
+	// Name is sysfs device name, e.g. "mem0".
+	Name   string
+	Serial uint64
+	// RAMSize and PMEMSize are volatile and persistent capacity in bytes.
+	RAMSize  uint64
+	PMEMSize uint64
+	// Node is NUMA node closest to the device host bridge, -1 if unknown.
+	Node int
+}
+
+// Region represent CXL region interleaved across memory devices.
+type Region struct {
+	// Name is sysfs device name, e.g. "region0".
+	Name string
+	// Mode is "ram" or "pmem".
+	Mode string
+	Size uint64
+	// Memdevs are names of memory devices backing the region.
+	Memdevs []string
+	// Node is NUMA node the region memory is surfaced as, -1 if region
+	// isn't onlined as system RAM.
+	Node int
+}
+
+// GetMemdevs returns CXL memory devices.
+func GetMemdevs(opts ...Option) ([]Memdev, error) {
+	devicesPath := newOptions(opts).devicesPath()
+
+	names, err := deviceNames(devicesPath, "mem")
+	if err != nil {
+		return nil, err
+	}
+
+	var memdevs []Memdev
+	for _, name := range names {
+		path := filepath.Join(devicesPath, name)
+
+		m := Memdev{Name: name}
+		if m.Serial, err = readHex(filepath.Join(path, "serial")); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if m.RAMSize, err = readHex(filepath.Join(path, "ram", "size")); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if m.PMEMSize, err = readHex(filepath.Join(path, "pmem", "size")); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if m.Node, err = readNode(filepath.Join(path, "numa_node")); err != nil {
+			return nil, err
+		}
+
+		memdevs = append(memdevs, m)
+	}
+
+	return memdevs, nil
+}
+
+// GetRegions returns CXL regions.
+func GetRegions(opts ...Option) ([]Region, error) {
+	devicesPath := newOptions(opts).devicesPath()
+
+	names, err := deviceNames(devicesPath, "region")
+	if err != nil {
+		return nil, err
+	}
+
+	var regions []Region
+	for _, name := range names {
+		r, err := parseRegion(devicesPath, name)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", name, err)
+		}
+		regions = append(regions, r)
+	}
+
+	return regions, nil
+}
+
+func parseRegion(devicesPath, name string) (Region, error) {
+	path := filepath.Join(devicesPath, name)
+	r := Region{Name: name, Node: -1}
+
+	mode, err := os.ReadFile(filepath.Join(path, "mode"))
+	if err != nil && !os.IsNotExist(err) {
+		return Region{}, err
+	}
+	r.Mode = strings.TrimSpace(string(mode))
+
+	if r.Size, err = readHex(filepath.Join(path, "size")); err != nil {
+		return Region{}, err
+	}
+
+	dir, err := os.ReadDir(path)
+	if err != nil {
+		return Region{}, err
+	}
+
+	for _, i := range dir {
+		// target0 contains endpoint decoder name, e.g. "decoder5.0".
+		if strings.HasPrefix(i.Name(), "target") {
+			decoder, err := os.ReadFile(filepath.Join(path, i.Name()))
+			if err != nil {
+				return Region{}, err
+			}
+			if strings.TrimSpace(string(decoder)) == "" {
+				continue
+			}
+
+			memdev, err := memdevOfDecoder(devicesPath, strings.TrimSpace(string(decoder)))
+			if err != nil {
+				return Region{}, err
+			}
+			if memdev != "" {
+				r.Memdevs = append(r.Memdevs, memdev)
+			}
+		}
+
+		// dax_region0/dax0.0/target_node
+		if strings.HasPrefix(i.Name(), "dax_region") {
+			node, err := daxRegionNode(filepath.Join(path, i.Name()))
+			if err != nil {
+				return Region{}, err
+			}
+			if node >= 0 {
+				r.Node = node
+			}
+		}
+	}
+
+	return r, nil
+}
+
+// memdevOfDecoder returns memory device owning endpoint decoder.
+func memdevOfDecoder(devicesPath, decoder string) (string, error) {
+	// /sys/devices/platform/ACPI0017:00/root0/port1/endpoint3/mem0/... or
+	// .../mem0/endpoint3/decoder5.0 depending on kernel version.
+	path, err := filepath.EvalSymlinks(filepath.Join(devicesPath, decoder))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	for _, c := range strings.Split(path, string(filepath.Separator)) {
+		if strings.HasPrefix(c, "mem") {
+			if _, err := strconv.Atoi(strings.TrimPrefix(c, "mem")); err == nil {
+				return c, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+func daxRegionNode(path string) (int, error) {
+	dir, err := os.ReadDir(path)
+	if err != nil {
+		return -1, err
+	}
+
+	for _, i := range dir {
+		if !strings.HasPrefix(i.Name(), "dax") || strings.HasPrefix(i.Name(), "dax_region") {
+			continue
+		}
+
+		node, err := readNode(filepath.Join(path, i.Name(), "target_node"))
+		if err != nil {
+			return -1, err
+		}
+		if node >= 0 {
+			return node, nil
+		}
+	}
+
+	return -1, nil
+}
+
+func deviceNames(devicesPath, prefix string) ([]string, error) {
+	dir, err := os.ReadDir(devicesPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var names []string
+	for _, i := range dir {
+		if !strings.HasPrefix(i.Name(), prefix) {
+			continue
+		}
+		if _, err := strconv.Atoi(strings.TrimPrefix(i.Name(), prefix)); err != nil {
+			continue
+		}
+		names = append(names, i.Name())
+	}
+
+	return names, nil
+}
+
+func readHex(path string) (uint64, error) {
+	f, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	// 0x10000000\n
+	v, err := strconv.ParseUint(strings.TrimPrefix(strings.TrimSpace(string(f)), "0x"), 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("convert %q: %w", path, err)
+	}
+
+	return v, nil
+}
+
+// readNode returns node ID from file or -1 if the file doesn't exist.
+func readNode(path string) (int, error) {
+	f, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return -1, nil
+		}
+		return -1, err
+	}
+
+	node, err := strconv.Atoi(strings.TrimSpace(string(f)))
+	if err != nil {
+		return -1, fmt.Errorf("convert %q: %w", path, err)
+	}
+
+	return node, nil
+}
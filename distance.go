@@ -0,0 +1,133 @@
+package numa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// GetDistanceMatrix returns NUMA distances between nodes.
+// Rows are in the same order as nodes returned by GetNodes.
+func GetDistanceMatrix(opts ...Option) ([][]int, error) {
+	return newOptions(opts).topologyProvider().Distances(context.Background())
+}
+
+// Distance returns distance from n to node id, false if it's unknown.
+func (n Node) Distance(id int) (int, bool) {
+	for i, nodeID := range n.distanceIDs() {
+		if nodeID == id {
+			return n.Distances[i], true
+		}
+	}
+	return 0, false
+}
+
+// distanceIDs returns node IDs of Distances columns.
+func (n Node) distanceIDs() []int {
+	if len(n.DistanceNodes) == len(n.Distances) {
+		return n.DistanceNodes
+	}
+
+	ids := make([]int, len(n.Distances))
+	for i := range ids {
+		ids[i] = i
+	}
+	return ids
+}
+
+// distanceNodes returns IDs of nodes in the order of distance columns.
+func distanceNodes(o options) ([]int, error) {
+	online, err := readNodeState(o, "online")
+	if err != nil {
+		return nil, err
+	}
+	return online.IDs(), nil
+}
+
+// NearestNodes returns IDs of other nodes ordered by distance from n,
+// nodes with equal distance are ordered by ID.
+func (n Node) NearestNodes() []int {
+	ids := make([]int, 0, len(n.Distances))
+	for _, id := range n.distanceIDs() {
+		if id != n.ID {
+			ids = append(ids, id)
+		}
+	}
+
+	slices.SortStableFunc(ids, func(a, b int) int {
+		return distanceTo(n, a) - distanceTo(n, b)
+	})
+
+	return ids
+}
+
+// NearestMemoryNode returns node of candidates closest to cpuNode, e.g.
+// CPU-less CXL memory node closest to CPUs of cpuNode. Nodes with memory
+// are candidates if candidates is empty. cpuNode itself is returned if it's
+// a candidate, nodes with equal distance are chosen by the lowest ID.
+func NearestMemoryNode(cpuNode int, candidates NodeSet, opts ...Option) (int, error) {
+	o := newOptions(opts)
+
+	distances, err := parseDistance(o.sys, path.Join("devices/system/node", fmt.Sprintf("node%d", cpuNode), "distance"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return -1, fmt.Errorf("node%d: %w: %w", cpuNode, ErrNodeNotFound, err)
+	}
+	if err != nil {
+		return -1, fmt.Errorf("parse distance: %w", err)
+	}
+
+	online, err := distanceNodes(o)
+	if err != nil {
+		return -1, fmt.Errorf("read online nodes: %w", err)
+	}
+	if len(online) != len(distances) {
+		return -1, fmt.Errorf("node%d: %d distances for %d online nodes", cpuNode, len(distances), len(online))
+	}
+
+	if candidates.Len() == 0 {
+		if candidates, err = readNodeState(o, "has_memory"); err != nil {
+			return -1, fmt.Errorf("read memory nodes: %w", err)
+		}
+	}
+
+	n := Node{ID: cpuNode, Distances: distances, DistanceNodes: online}
+	nearest := -1
+	for _, id := range candidates.IDs() {
+		if _, ok := n.Distance(id); !ok {
+			continue
+		}
+		if nearest == -1 || distanceTo(n, id) < distanceTo(n, nearest) {
+			nearest = id
+		}
+	}
+
+	if nearest == -1 {
+		return -1, fmt.Errorf("nearest memory node of node%d: %w", cpuNode, ErrNodeNotFound)
+	}
+
+	return nearest, nil
+}
+
+func parseDistance(fsys fs.FS, name string) ([]int, error) {
+	f, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// 10 21\n
+	var distances []int
+	for _, token := range strings.Fields(string(f)) {
+		d, err := strconv.Atoi(token)
+		if err != nil {
+			return nil, fmt.Errorf("convert distance %q: %w", token, err)
+		}
+		distances = append(distances, d)
+	}
+
+	return distances, nil
+}
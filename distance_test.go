@@ -0,0 +1,53 @@
+package numa
+
+import (
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestNearestNodesSparseIDs(t *testing.T) {
+	n := Node{ID: 2, Distances: []int{20, 10, 30}, DistanceNodes: []int{0, 2, 5}}
+
+	if got, want := n.NearestNodes(), []int{0, 5}; !slices.Equal(got, want) {
+		t.Errorf("NearestNodes() = %v, want %v", got, want)
+	}
+	if d, ok := n.Distance(5); !ok || d != 30 {
+		t.Errorf("Distance(5) = %d, %t, want 30, true", d, ok)
+	}
+	if _, ok := n.Distance(1); ok {
+		t.Error("Distance(1) of node not in distances is known")
+	}
+}
+
+func TestNearestMemoryNodeSparseIDs(t *testing.T) {
+	fsys := fstest.MapFS{
+		"devices/system/node/online":         {Data: []byte("0,2\n")},
+		"devices/system/node/has_memory":     {Data: []byte("0,2\n")},
+		"devices/system/node/node0/distance": {Data: []byte("10 21\n")},
+		"devices/system/node/node2/distance": {Data: []byte("21 10\n")},
+	}
+
+	tests := []struct {
+		cpuNode    int
+		candidates NodeSet
+		want       int
+	}{
+		{0, NewNodeSet(2), 2},
+		{2, NewNodeSet(0, 2), 2},
+		{0, NodeSet{}, 0},
+	}
+	for _, tt := range tests {
+		got, err := NearestMemoryNode(tt.cpuNode, tt.candidates, WithSysFS(fsys))
+		if err != nil {
+			t.Fatalf("NearestMemoryNode(%d, %s): %v", tt.cpuNode, tt.candidates, err)
+		}
+		if got != tt.want {
+			t.Errorf("NearestMemoryNode(%d, %s) = %d, want %d", tt.cpuNode, tt.candidates, got, tt.want)
+		}
+	}
+
+	if _, err := NearestMemoryNode(0, NewNodeSet(1), WithSysFS(fsys)); err == nil {
+		t.Error("NearestMemoryNode with offline candidate returned no error")
+	}
+}
@@ -0,0 +1,146 @@
+package numa
+
+import (
+	"context"
+	"time"
+)
+
+// DriftConfig configures WatchDrift.
+type DriftConfig struct {
+	// Interval between samples, 10 seconds if zero.
+	Interval time.Duration
+	// MemoryThreshold is share of process memory outside of expected nodes,
+	// from 0 to 1, above which process is drifting. 0.2 if zero.
+	MemoryThreshold float64
+	// RuntimeThreshold is share of process CPU time spent outside of expected
+	// nodes since previous sample, from 0 to 1, above which process is drifting.
+	// 0.2 if zero.
+	RuntimeThreshold float64
+	// Repeat sends event on every sample while process is drifting,
+	// not only when it starts drifting.
+	Repeat bool
+}
+
+// DriftEvent represent change of process drift state.
+type DriftEvent struct {
+	Time time.Time
+	PID  int
+	// Drifting is true when process exceeded a threshold and false when
+	// it returned back below thresholds.
+	Drifting bool
+	// MemoryOffNode is share of process memory outside of expected nodes.
+	MemoryOffNode float64
+	// RuntimeOffNode is share of CPU time spent outside of expected nodes
+	// since previous sample.
+	RuntimeOffNode float64
+	// Usage is process memory in bytes keyed by node ID.
+	Usage map[int]uint64
+	// Err is set when process can't be sampled anymore, e.g. it exited.
+	// The channel is closed after such event.
+	Err error
+}
+
+// WatchDrift samples threads and memory of process pid every interval and sends
+// event to the returned channel when process starts or stops drifting from expected
+// nodes. The channel is closed when ctx is done or process can't be sampled.
+func WatchDrift(ctx context.Context, pid int, nodes NodeSet, cfg DriftConfig) <-chan DriftEvent {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 10 * time.Second
+	}
+	if cfg.MemoryThreshold <= 0 {
+		cfg.MemoryThreshold = 0.2
+	}
+	if cfg.RuntimeThreshold <= 0 {
+		cfg.RuntimeThreshold = 0.2
+	}
+
+	events := make(chan DriftEvent)
+	go func() {
+		defer close(events)
+
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+
+		var drifting bool
+		previous := make(map[int]uint64)
+		for {
+			e := sampleDrift(ctx, pid, nodes, previous)
+			if ctx.Err() != nil {
+				return
+			}
+			if e.Err == nil {
+				e.Drifting = e.MemoryOffNode > cfg.MemoryThreshold || e.RuntimeOffNode > cfg.RuntimeThreshold
+			}
+
+			if e.Err != nil || e.Drifting != drifting || e.Drifting && cfg.Repeat {
+				drifting = e.Drifting
+				select {
+				case events <- e:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if e.Err != nil {
+				return
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events
+}
+
+// sampleDrift measures process drift, previous keeps CPU time of threads
+// between samples and is updated.
+func sampleDrift(ctx context.Context, pid int, nodes NodeSet, previous map[int]uint64) DriftEvent {
+	e := DriftEvent{Time: time.Now(), PID: pid}
+
+	usage, err := ProcessNodeUsageContext(ctx, pid)
+	if err != nil {
+		e.Err = err
+		return e
+	}
+	e.Usage = usage
+	e.MemoryOffNode = 1 - localityScore(usage, nodes)
+
+	threads, err := GetThreads(pid)
+	if err != nil {
+		e.Err = err
+		return e
+	}
+
+	var total, offNode uint64
+	current := make(map[int]uint64, len(threads))
+	for _, t := range threads {
+		ticks := t.UserTime + t.SystemTime
+		current[t.TID] = ticks
+
+		var delta uint64
+		if ticks > previous[t.TID] {
+			delta = ticks - previous[t.TID]
+		}
+
+		total += delta
+		if !nodes.Contains(t.Node) {
+			offNode += delta
+		}
+	}
+
+	for tid := range previous {
+		delete(previous, tid)
+	}
+	for tid, ticks := range current {
+		previous[tid] = ticks
+	}
+
+	if total > 0 {
+		e.RuntimeOffNode = float64(offNode) / float64(total)
+	}
+
+	return e
+}
@@ -0,0 +1,69 @@
+package numa
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// RecordVersion is schema version of Record, it's incremented on incompatible changes.
+const RecordVersion = 2
+
+// Record represent JSON object written by Emitter for every sample.
+type Record struct {
+	Version int       `json:"version"`
+	Time    time.Time `json:"time"`
+	Nodes   []Node    `json:"nodes,omitempty"`
+	// Error is set if reading nodes failed, Nodes contain only nodes read successfully then.
+	Error string `json:"error,omitempty"`
+}
+
+// Emitter writes one JSON object per line with nodes information.
+type Emitter struct {
+	enc      *json.Encoder
+	interval time.Duration
+	opts     []Option
+}
+
+// NewEmitter returns Emitter writing nodes read with GetNodes options to w every interval,
+// non-positive interval means 10 seconds.
+func NewEmitter(w io.Writer, interval time.Duration, opts ...Option) *Emitter {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	return &Emitter{enc: json.NewEncoder(w), interval: interval, opts: opts}
+}
+
+// Emit reads nodes and writes single record. Errors of reading nodes are
+// written as records, only write errors are returned.
+func (e *Emitter) Emit() error {
+	r := Record{Version: RecordVersion, Time: time.Now()}
+
+	nodes, err := GetNodes(e.opts...)
+	r.Nodes = nodes
+	if err != nil {
+		r.Error = err.Error()
+	}
+
+	return e.enc.Encode(r)
+}
+
+// Run emits record immediately and then every interval until ctx is done
+// or writing fails. It returns nil when ctx is done.
+func (e *Emitter) Run(ctx context.Context) error {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.Emit(); err != nil {
+			return err
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
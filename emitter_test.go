@@ -0,0 +1,29 @@
+package numa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestEmitterZeroInterval(t *testing.T) {
+	p := &sequenceProvider{sequence: [][]Node{{{ID: 0}}}}
+
+	var b bytes.Buffer
+	e := NewEmitter(&b, 0, WithProvider(p))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := e.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	var r Record
+	if err := json.Unmarshal(b.Bytes(), &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.Version != RecordVersion || len(r.Nodes) != 1 {
+		t.Errorf("record = %+v, want version %d with single node", r, RecordVersion)
+	}
+}
@@ -0,0 +1,84 @@
+package numa
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// emulatedSizeTolerance is maximum difference of memory of emulated nodes.
+// Kernel splits memory between emulated nodes evenly rounding to 32 MiB,
+// while first node of real machines loses more to firmware and low memory holes.
+const emulatedSizeTolerance = 64 << 20
+
+// Emulation describe NUMA emulation detected by DetectEmulation.
+// Emulated nodes share the same physical memory and CPUs of emulated nodes
+// don't have faster access to their memory, so they shouldn't be used for
+// locality decisions.
+type Emulation struct {
+	// Emulated is set if kernel command line enables emulation.
+	Emulated bool `json:"emulated" yaml:"emulated"`
+	// CmdLine is numa=fake argument of kernel command line, e.g. "numa=fake=4".
+	CmdLine string `json:"cmdline,omitempty" yaml:"cmdline,omitempty"`
+	// UniformSizes is set if there are several nodes with memory and their
+	// sizes differ less than emulated nodes do. It's only a hint as nodes of
+	// symmetric machines and vNUMA guests could have such sizes too.
+	UniformSizes bool `json:"uniform_sizes" yaml:"uniform_sizes"`
+}
+
+// DetectEmulation reports whether nodes are emulated with numa=fake kernel
+// parameter of CONFIG_NUMA_EMU kernels, e.g. on test machines.
+func DetectEmulation(opts ...Option) (Emulation, error) {
+	o := newOptions(opts)
+
+	nodes, err := GetNodes(opts...)
+	if err != nil {
+		return Emulation{}, err
+	}
+
+	return detectEmulation(o, nodes)
+}
+
+func detectEmulation(o options, nodes []Node) (Emulation, error) {
+	var e Emulation
+
+	cmdline, err := fs.ReadFile(o.proc, "cmdline")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return Emulation{}, fmt.Errorf("read cmdline: %w", err)
+	}
+	for _, arg := range strings.Fields(string(cmdline)) {
+		if strings.HasPrefix(arg, "numa=fake") {
+			e.CmdLine = arg
+		}
+	}
+
+	e.UniformSizes = uniformSizes(nodes)
+	e.Emulated = e.CmdLine != ""
+	if e.Emulated || e.UniformSizes {
+		o.debug("NUMA emulation hints found", "cmdline", e.CmdLine, "uniform_sizes", e.UniformSizes)
+	}
+
+	return e, nil
+}
+
+// uniformSizes reports whether there are at least two nodes with memory
+// and their sizes differ at most by emulatedSizeTolerance.
+func uniformSizes(nodes []Node) bool {
+	var count int
+	var lowest, highest uint64
+	for _, n := range nodes {
+		if n.MemTotal == 0 {
+			continue
+		}
+		if count == 0 || n.MemTotal < lowest {
+			lowest = n.MemTotal
+		}
+		if n.MemTotal > highest {
+			highest = n.MemTotal
+		}
+		count++
+	}
+
+	return count > 1 && highest-lowest <= emulatedSizeTolerance
+}
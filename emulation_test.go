@@ -0,0 +1,38 @@
+package numa
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestDetectEmulation(t *testing.T) {
+	uniform := []Node{{ID: 0, MemTotal: 8 << 30}, {ID: 1, MemTotal: 8<<30 - 32<<20}}
+	distinct := []Node{{ID: 0, MemTotal: 8 << 30}, {ID: 1, MemTotal: 7 << 30}}
+
+	tests := []struct {
+		name     string
+		cmdline  string
+		nodes    []Node
+		emulated bool
+		uniform  bool
+	}{
+		{"fake", "ro numa=fake=2 quiet\n", uniform, true, true},
+		{"fake distinct sizes", "numa=fake=2U\n", distinct, true, false},
+		// Uniform sizes alone, e.g. symmetric machine or vNUMA guest, are only a hint.
+		{"uniform sizes", "ro quiet\n", uniform, false, true},
+		{"real", "ro quiet\n", distinct, false, false},
+		{"single node", "ro\n", []Node{{ID: 0, MemTotal: 8 << 30}}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newOptions([]Option{WithProcFS(fstest.MapFS{"cmdline": {Data: []byte(tt.cmdline)}})})
+			e, err := detectEmulation(o, tt.nodes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if e.Emulated != tt.emulated || e.UniformSizes != tt.uniform {
+				t.Errorf("detectEmulation() = %+v, want emulated %t, uniform sizes %t", e, tt.emulated, tt.uniform)
+			}
+		})
+	}
+}
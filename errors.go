@@ -0,0 +1,32 @@
+package numa
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+// Errors returned by readers wrapping underlying errors, check them with errors.Is.
+var (
+	// ErrNoNUMA means kernel doesn't expose NUMA nodes, e.g. it's built
+	// without CONFIG_NUMA or sysfs isn't mounted, and GetNodes can't
+	// synthesize single node from procfs either.
+	ErrNoNUMA = errors.New("NUMA not available")
+	// ErrNodeNotFound means requested node doesn't exist or isn't available
+	// in the current cpuset cgroup.
+	ErrNodeNotFound = errors.New("node not found")
+	// ErrPermission is fs.ErrPermission, so every error caused by missing
+	// privileges matches it including syscall.EPERM and syscall.EACCES.
+	ErrPermission = fs.ErrPermission
+	// ErrUnsupportedKernel means running kernel doesn't implement a feature,
+	// e.g. system call returned ENOSYS.
+	ErrUnsupportedKernel = errors.New("not supported by kernel")
+)
+
+// noNUMAErr wraps err with ErrNoNUMA if sysfs node directory doesn't exist.
+func noNUMAErr(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %w", ErrNoNUMA, err)
+	}
+	return err
+}
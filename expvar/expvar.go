@@ -0,0 +1,32 @@
+// Package expvar publishes NUMA nodes under expvar.
+package expvar
+
+import (
+	"expvar"
+
+	"github.com/oneumyvakin/numa"
+)
+
+// Publish publishes nodes read using opts under name, e.g. "numa".
+// Nodes are read every time the variable is read, e.g. on /debug/vars request.
+// Like expvar.Publish it panics if name is already registered.
+func Publish(name string, opts ...numa.Option) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return read(opts)
+	}))
+}
+
+// vars represent published value, Error is set if reading nodes failed
+// and Nodes contain only nodes read successfully then.
+type vars struct {
+	Nodes []numa.Node `json:"nodes,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
+
+func read(opts []numa.Option) vars {
+	nodes, err := numa.GetNodes(opts...)
+	if err != nil {
+		return vars{Nodes: nodes, Error: err.Error()}
+	}
+	return vars{Nodes: nodes}
+}
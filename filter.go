@@ -0,0 +1,71 @@
+package numa
+
+// Predicate reports whether node matches condition.
+type Predicate func(Node) bool
+
+// HasCPUs matches nodes with CPUs. Unlike Node.HasCPU it respects CPUs
+// removed by WithCgroupFiltering.
+func HasCPUs() Predicate {
+	return func(n Node) bool {
+		return len(n.CPU) > 0
+	}
+}
+
+// HasMemory matches nodes with memory. Unlike Node.HasMemory it respects
+// memory zeroed by WithCgroupFiltering.
+func HasMemory() Predicate {
+	return func(n Node) bool {
+		return n.MemTotal > 0
+	}
+}
+
+// IsOnline matches online nodes.
+func IsOnline() Predicate {
+	return func(n Node) bool {
+		return n.Online
+	}
+}
+
+// InSet matches nodes in set.
+func InSet(s NodeSet) Predicate {
+	return func(n Node) bool {
+		return s.Contains(n.ID)
+	}
+}
+
+// Not matches nodes not matching p.
+func Not(p Predicate) Predicate {
+	return func(n Node) bool {
+		return !p(n)
+	}
+}
+
+// Or matches nodes matching any of predicates.
+func Or(ps ...Predicate) Predicate {
+	return func(n Node) bool {
+		for _, p := range ps {
+			if p(n) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Filter returns nodes matching all predicates.
+func (l NodeList) Filter(ps ...Predicate) NodeList {
+	var filtered NodeList
+	for _, n := range l {
+		matches := true
+		for _, p := range ps {
+			if !p(n) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			filtered = append(filtered, n)
+		}
+	}
+	return filtered
+}
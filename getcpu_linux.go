@@ -0,0 +1,35 @@
+package numa
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// CurrentCPU returns CPU the calling thread is running on.
+// Goroutine could be rescheduled to another CPU right after the call
+// unless its thread is pinned.
+func CurrentCPU() (int, error) {
+	cpu, _, err := getcpu()
+	return cpu, err
+}
+
+// CurrentNode returns NUMA node the calling thread is running on.
+func CurrentNode() (int, error) {
+	_, node, err := getcpu()
+	return node, err
+}
+
+func getcpu() (int, int, error) {
+	var cpu, node uint32
+	_, _, errno := syscall.RawSyscall(sysGetcpu,
+		uintptr(unsafe.Pointer(&cpu)), uintptr(unsafe.Pointer(&node)), 0)
+	if errno == syscall.ENOSYS {
+		return 0, 0, fmt.Errorf("getcpu: %w: %w", ErrUnsupportedKernel, errno)
+	}
+	if errno != 0 {
+		return 0, 0, fmt.Errorf("getcpu: %w", errno)
+	}
+
+	return int(cpu), int(node), nil
+}
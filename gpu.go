@@ -0,0 +1,60 @@
+package numa
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GPU represent graphics device and its local NUMA node.
+type GPU struct {
+	// Card is DRM card name, e.g. "card0".
+	Card string
+	// Address is device address, e.g. PCI address "0000:3b:00.0".
+	Address string
+	// Node is -1 if firmware doesn't report device locality.
+	Node int
+}
+
+// GetGPUs returns GPUs with their local NUMA nodes.
+func GetGPUs(opts ...Option) ([]GPU, error) {
+	o := newOptions(opts)
+	path := o.sysPath("class/drm")
+
+	dir, err := os.ReadDir(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var gpus []GPU
+	for _, i := range dir {
+		// card0, but not connectors like card0-HDMI-A-1
+		if !strings.HasPrefix(i.Name(), "card") || strings.Contains(i.Name(), "-") {
+			continue
+		}
+
+		device, err := filepath.EvalSymlinks(filepath.Join(path, i.Name(), "device"))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		node, err := nodeForDevicePath(o, device)
+		if err != nil {
+			return nil, err
+		}
+
+		gpus = append(gpus, GPU{
+			Card:    i.Name(),
+			Address: filepath.Base(device),
+			Node:    node,
+		})
+	}
+
+	return gpus, nil
+}
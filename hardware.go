@@ -0,0 +1,51 @@
+package numa
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NodeList is list of nodes, e.g. returned by GetNodes.
+type NodeList []Node
+
+// String returns nodes formatted like `numactl --hardware`.
+func (l NodeList) String() string {
+	var b strings.Builder
+
+	var ids NodeSet
+	for _, n := range l {
+		ids.Add(n.ID)
+	}
+	fmt.Fprintf(&b, "available: %d nodes (%s)\n", len(l), ids)
+
+	for _, n := range l {
+		fmt.Fprintf(&b, "node %d cpus:", n.ID)
+		for _, cpu := range n.CPU {
+			fmt.Fprintf(&b, " %d", cpu)
+		}
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "node %d size: %d MB\n", n.ID, n.MemTotal>>20)
+		fmt.Fprintf(&b, "node %d free: %d MB\n", n.ID, n.MemFree>>20)
+	}
+
+	b.WriteString("node distances:\n")
+	b.WriteString("node ")
+	for _, n := range l {
+		fmt.Fprintf(&b, "%3d ", n.ID)
+	}
+	b.WriteString("\n")
+	for _, n := range l {
+		fmt.Fprintf(&b, "%3d: ", n.ID)
+		// Distances has columns of all online nodes, not only of nodes in l.
+		for _, m := range l {
+			if d, ok := n.Distance(m.ID); ok {
+				fmt.Fprintf(&b, "%3d ", d)
+			} else {
+				fmt.Fprintf(&b, "%3s ", "-")
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,26 @@
+package numa
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNodeListStringDistances(t *testing.T) {
+	online := []int{0, 2, 3}
+	l := NodeList{
+		{ID: 0, Distances: []int{10, 20, 30}, DistanceNodes: online},
+		// Node 2 is filtered out, node 5 has no distances.
+		{ID: 3, Distances: []int{30, 20, 10}, DistanceNodes: online},
+		{ID: 5},
+	}
+
+	s := l.String()
+	_, table, _ := strings.Cut(s, "node distances:\n")
+	want := "node   0   3   5 \n" +
+		"  0:  10  30   - \n" +
+		"  3:  30  10   - \n" +
+		"  5:   -   -   - \n"
+	if table != want {
+		t.Errorf("distances =\n%s\nwant\n%s", table, want)
+	}
+}
@@ -0,0 +1,162 @@
+package numa
+
+import "sync"
+
+// EMA is exponential moving average of metric. Zero value isn't usable, use NewEMA.
+type EMA struct {
+	alpha float64
+	value float64
+	ok    bool
+}
+
+// NewEMA returns EMA with smoothing factor alpha in range (0, 1],
+// higher alpha discounts older values faster.
+func NewEMA(alpha float64) *EMA {
+	if alpha <= 0 || alpha > 1 {
+		alpha = 1
+	}
+	return &EMA{alpha: alpha}
+}
+
+// Update adds value and returns smoothed value. The first value is taken as is.
+func (e *EMA) Update(v float64) float64 {
+	if !e.ok {
+		e.value, e.ok = v, true
+		return v
+	}
+	e.value += e.alpha * (v - e.value)
+	return e.value
+}
+
+// Value returns smoothed value, false is returned if there were no updates.
+func (e *EMA) Value() (float64, bool) {
+	return e.value, e.ok
+}
+
+// HealthState represent node health derived from smoothed metrics.
+type HealthState int
+
+const (
+	Healthy HealthState = iota
+	Pressure
+	Critical
+)
+
+func (s HealthState) String() string {
+	switch s {
+	case Healthy:
+		return "healthy"
+	case Pressure:
+		return "pressure"
+	case Critical:
+		return "critical"
+	}
+	return "unknown"
+}
+
+// HealthConfig represent smoothing factor and thresholds of node health states.
+// Zero values are replaced with defaults.
+type HealthConfig struct {
+	// Alpha is EMA smoothing factor, 0.3 by default.
+	Alpha float64
+	// PressureAvailable and CriticalAvailable are fractions of MemTotal
+	// smoothed MemAvailable should fall below, 0.2 and 0.05 by default.
+	PressureAvailable float64
+	CriticalAvailable float64
+	// PressureMissRate and CriticalMissRate are smoothed numa_miss per second
+	// rates to exceed, thresholds are disabled if zero.
+	PressureMissRate float64
+	CriticalMissRate float64
+}
+
+// NodeHealth represent smoothed metrics and health state of node.
+type NodeHealth struct {
+	Node         int
+	MemAvailable float64
+	MissRate     float64
+	State        HealthState
+}
+
+// HealthMonitor keeps smoothed metrics of nodes. It's safe for concurrent use.
+type HealthMonitor struct {
+	mu    sync.Mutex
+	cfg   HealthConfig
+	nodes map[int]*nodeEMA
+}
+
+type nodeEMA struct {
+	memAvailable *EMA
+	missRate     *EMA
+	health       NodeHealth
+}
+
+// NewHealthMonitor returns HealthMonitor with config.
+func NewHealthMonitor(cfg HealthConfig) *HealthMonitor {
+	if cfg.Alpha == 0 {
+		cfg.Alpha = 0.3
+	}
+	if cfg.PressureAvailable == 0 {
+		cfg.PressureAvailable = 0.2
+	}
+	if cfg.CriticalAvailable == 0 {
+		cfg.CriticalAvailable = 0.05
+	}
+	return &HealthMonitor{cfg: cfg, nodes: make(map[int]*nodeEMA)}
+}
+
+// Update adds node metrics and returns health of the updated nodes.
+func (m *HealthMonitor) Update(metrics []NodeMetrics) []NodeHealth {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	health := make([]NodeHealth, 0, len(metrics))
+	for _, nm := range metrics {
+		n, ok := m.nodes[nm.Node.ID]
+		if !ok {
+			n = &nodeEMA{memAvailable: NewEMA(m.cfg.Alpha), missRate: NewEMA(m.cfg.Alpha)}
+			m.nodes[nm.Node.ID] = n
+		}
+
+		memAvailable := n.memAvailable.Update(float64(nm.Node.MemAvailable))
+		// Without rates the previous smoothed miss rate is kept.
+		rate, _ := n.missRate.Value()
+		if nm.Rates != nil {
+			rate = n.missRate.Update(nm.Rates["numa_miss"])
+		}
+
+		n.health = NodeHealth{
+			Node:         nm.Node.ID,
+			MemAvailable: memAvailable,
+			MissRate:     rate,
+			State:        m.state(memAvailable, float64(nm.Node.MemTotal), rate),
+		}
+		health = append(health, n.health)
+	}
+
+	return health
+}
+
+// Health returns the last health of node, false is returned if node wasn't updated.
+func (m *HealthMonitor) Health(nodeID int) (NodeHealth, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	n, ok := m.nodes[nodeID]
+	if !ok {
+		return NodeHealth{}, false
+	}
+	return n.health, true
+}
+
+func (m *HealthMonitor) state(memAvailable, memTotal, missRate float64) HealthState {
+	c := m.cfg
+	switch {
+	case memTotal > 0 && memAvailable < memTotal*c.CriticalAvailable,
+		c.CriticalMissRate > 0 && missRate > c.CriticalMissRate:
+		return Critical
+	case memTotal > 0 && memAvailable < memTotal*c.PressureAvailable,
+		c.PressureMissRate > 0 && missRate > c.PressureMissRate:
+		return Pressure
+	}
+	return Healthy
+}
@@ -0,0 +1,121 @@
+package numa
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// NodeSample represent node information at a point in time.
+type NodeSample struct {
+	Time time.Time
+	Node Node
+}
+
+// Summary represent minimum, maximum and average of metric over samples.
+type Summary struct {
+	Min   float64
+	Max   float64
+	Avg   float64
+	Count int
+}
+
+// History retains the last samples of every node. It's safe for concurrent use.
+type History struct {
+	mu      sync.RWMutex
+	size    int
+	samples map[int]*sampleRing
+}
+
+// sampleRing is a ring buffer of node samples in chronological order.
+type sampleRing struct {
+	samples []NodeSample
+	next    int
+}
+
+// NewHistory returns History retaining the last size samples of every node.
+func NewHistory(size int) *History {
+	if size < 1 {
+		size = 1
+	}
+	return &History{size: size, samples: make(map[int]*sampleRing)}
+}
+
+// Add records nodes sampled at t.
+func (h *History) Add(t time.Time, nodes []Node) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, n := range nodes {
+		r, ok := h.samples[n.ID]
+		if !ok {
+			r = &sampleRing{samples: make([]NodeSample, 0, h.size)}
+			h.samples[n.ID] = r
+		}
+
+		s := NodeSample{Time: t, Node: n}
+		if len(r.samples) < h.size {
+			r.samples = append(r.samples, s)
+			continue
+		}
+		r.samples[r.next] = s
+		r.next = (r.next + 1) % h.size
+	}
+}
+
+// AddSnapshot records nodes of watcher snapshot, snapshots with error are ignored.
+func (h *History) AddSnapshot(s Snapshot) {
+	if s.Err != nil {
+		return
+	}
+	h.Add(s.Time, s.Nodes)
+}
+
+// Samples returns samples of node taken at or after since in chronological order.
+func (h *History) Samples(nodeID int, since time.Time) []NodeSample {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	r, ok := h.samples[nodeID]
+	if !ok {
+		return nil
+	}
+
+	var samples []NodeSample
+	for i := range r.samples {
+		s := r.samples[(r.next+i)%len(r.samples)]
+		if !s.Time.Before(since) {
+			samples = append(samples, s)
+		}
+	}
+
+	return samples
+}
+
+// Summarize returns summary of metric of node samples taken within window before now.
+// It returns false if there are no such samples.
+func (h *History) Summarize(nodeID int, window time.Duration, metric func(Node) float64) (Summary, bool) {
+	samples := h.Samples(nodeID, time.Now().Add(-window))
+	if len(samples) == 0 {
+		return Summary{}, false
+	}
+
+	s := Summary{Min: math.Inf(1), Max: math.Inf(-1), Count: len(samples)}
+	var sum float64
+	for _, sample := range samples {
+		v := metric(sample.Node)
+		s.Min = math.Min(s.Min, v)
+		s.Max = math.Max(s.Max, v)
+		sum += v
+	}
+	s.Avg = sum / float64(len(samples))
+
+	return s, true
+}
+
+// MemAvailable returns summary of node MemAvailable within window before now.
+func (h *History) MemAvailable(nodeID int, window time.Duration) (Summary, bool) {
+	return h.Summarize(nodeID, window, func(n Node) float64 {
+		return float64(n.MemAvailable)
+	})
+}
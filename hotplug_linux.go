@@ -0,0 +1,173 @@
+package numa
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// netlinkKobjectUevent is NETLINK_KOBJECT_UEVENT protocol, syscall package doesn't define it.
+const netlinkKobjectUevent = 15
+
+// HotplugEvent represent kernel uevent of node, CPU or memory block.
+type HotplugEvent struct {
+	// Action is "add", "remove", "online", "offline", "change" or HotplugResync.
+	Action string
+	// Subsystem is "node", "cpu" or "memory".
+	Subsystem string
+	// DevPath is sysfs device path, e.g. "/devices/system/cpu/cpu4".
+	DevPath string
+	// ID is node, CPU or memory block number, -1 if unknown.
+	ID int
+	// Env contains all uevent variables.
+	Env map[string]string
+}
+
+// HotplugResync is Action of event sent when uevent socket buffer overflowed
+// and events were lost, e.g. on burst of memory block events. Nodes should
+// be read again as no event of the lost changes is sent.
+const HotplugResync = "resync"
+
+// HotplugWatcher delivers node, CPU and memory hotplug events to C.
+type HotplugWatcher struct {
+	C <-chan HotplugEvent
+
+	mu  sync.Mutex
+	err error
+}
+
+// Err returns error which stopped the watcher once C is closed,
+// nil if it was stopped because context is done.
+func (w *HotplugWatcher) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.err
+}
+
+// WatchHotplug subscribes to kernel uevents and sends node, CPU and memory
+// hotplug events to C of the returned watcher. C is closed when ctx is done
+// or reading fails, see Err. Subscribing requires network namespace of the host.
+func WatchHotplug(ctx context.Context) (*HotplugWatcher, error) {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC|syscall.SOCK_NONBLOCK, netlinkKobjectUevent)
+	if err != nil {
+		return nil, fmt.Errorf("uevent socket: %w", err)
+	}
+
+	// Group 1 receives uevents broadcast by kernel.
+	if err := syscall.Bind(fd, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK, Groups: 1}); err != nil {
+		syscall.Close(fd)
+		return nil, fmt.Errorf("uevent bind: %w", err)
+	}
+
+	// Non-blocking file is served by runtime poller, so Close interrupts Read.
+	f := os.NewFile(uintptr(fd), "uevent")
+
+	return newHotplugWatcher(ctx, f), nil
+}
+
+// newHotplugWatcher reads uevents from f until ctx is done or reading fails,
+// f is closed in both cases.
+func newHotplugWatcher(ctx context.Context, f io.ReadCloser) *HotplugWatcher {
+	events := make(chan HotplugEvent)
+	w := &HotplugWatcher{C: events}
+
+	// stopped stops closing goroutine when reading stops before ctx is done.
+	stopped := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			f.Close()
+		case <-stopped:
+		}
+	}()
+
+	go func() {
+		defer close(events)
+		defer close(stopped)
+		defer f.Close()
+
+		send := func(e HotplugEvent) bool {
+			select {
+			case events <- e:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		buf := make([]byte, 64*1024)
+		for {
+			n, err := f.Read(buf)
+			if errors.Is(err, syscall.ENOBUFS) {
+				if !send(HotplugEvent{Action: HotplugResync, ID: -1}) {
+					return
+				}
+				continue
+			}
+			if err != nil {
+				if ctx.Err() == nil {
+					w.mu.Lock()
+					w.err = fmt.Errorf("read uevent: %w", err)
+					w.mu.Unlock()
+				}
+				return
+			}
+
+			e, ok := parseUevent(buf[:n])
+			if !ok {
+				continue
+			}
+
+			if !send(e) {
+				return
+			}
+		}
+	}()
+
+	return w
+}
+
+// parseUevent parses kernel uevent "action@devpath\0KEY=VALUE\0..." and reports
+// whether it's a node, CPU or memory event.
+func parseUevent(msg []byte) (HotplugEvent, bool) {
+	parts := bytes.Split(msg, []byte{0})
+	if len(parts) < 2 || !bytes.Contains(parts[0], []byte("@")) {
+		// udev messages start with "libudev" header.
+		return HotplugEvent{}, false
+	}
+
+	e := HotplugEvent{ID: -1, Env: make(map[string]string)}
+	for _, p := range parts[1:] {
+		key, value, ok := strings.Cut(string(p), "=")
+		if ok {
+			e.Env[key] = value
+		}
+	}
+
+	e.Action = e.Env["ACTION"]
+	e.Subsystem = e.Env["SUBSYSTEM"]
+	e.DevPath = e.Env["DEVPATH"]
+
+	switch e.Subsystem {
+	case "node", "cpu", "memory":
+	default:
+		return HotplugEvent{}, false
+	}
+
+	// /devices/system/memory/memory32
+	name := filepath.Base(e.DevPath)
+	if id, err := strconv.Atoi(strings.TrimLeft(name, "abcdefghijklmnopqrstuvwxyz")); err == nil {
+		e.ID = id
+	}
+
+	return e, true
+}
@@ -0,0 +1,75 @@
+package numa
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestParseUevent(t *testing.T) {
+	msg := []byte("online@/devices/system/memory/memory32\x00ACTION=online\x00DEVPATH=/devices/system/memory/memory32\x00SUBSYSTEM=memory\x00SEQNUM=1\x00")
+	e, ok := parseUevent(msg)
+	if !ok {
+		t.Fatal("memory event isn't parsed")
+	}
+	if e.Action != "online" || e.Subsystem != "memory" || e.ID != 32 || e.Env["SEQNUM"] != "1" {
+		t.Errorf("event = %+v", e)
+	}
+
+	for _, msg := range [][]byte{
+		[]byte("libudev\x00ACTION=add\x00SUBSYSTEM=cpu\x00"),
+		[]byte("add@/devices/pci0000:00\x00ACTION=add\x00SUBSYSTEM=pci\x00"),
+		[]byte("add@/devices/system/cpu/cpu1"),
+	} {
+		if e, ok := parseUevent(msg); ok {
+			t.Errorf("parseUevent(%q) = %+v, want not hotplug event", msg, e)
+		}
+	}
+}
+
+func TestWatchHotplugContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	w, err := WatchHotplug(ctx)
+	if err != nil {
+		t.Skipf("uevent socket isn't available: %v", err)
+	}
+	cancel()
+
+	select {
+	case <-w.C:
+	case <-time.After(time.Second):
+		t.Fatal("C isn't closed after context is done")
+	}
+	for range w.C {
+	}
+	if err := w.Err(); err != nil {
+		t.Errorf("Err() = %v, want nil after context is done", err)
+	}
+}
+
+func TestHotplugWatcherReadError(t *testing.T) {
+	r, wr, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := newHotplugWatcher(context.Background(), r)
+	wr.Close()
+
+	select {
+	case _, ok := <-w.C:
+		if ok {
+			t.Fatal("unexpected event")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("C isn't closed after read error")
+	}
+	if err := w.Err(); !errors.Is(err, io.EOF) {
+		t.Errorf("Err() = %v, want %v", err, io.EOF)
+	}
+	if _, err := r.Read(make([]byte, 1)); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("read after watcher stopped = %v, want %v", err, os.ErrClosed)
+	}
+}
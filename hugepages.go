@@ -0,0 +1,61 @@
+package numa
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Hugepages represent hugepages accounting of a single page size on NUMA node.
+type Hugepages struct {
+	Total   uint64 `json:"total" yaml:"total"`
+	Free    uint64 `json:"free" yaml:"free"`
+	Surplus uint64 `json:"surplus" yaml:"surplus"`
+}
+
+func parseHugepages(fsys fs.FS, name string) (map[uint64]Hugepages, error) {
+	dir, err := fs.ReadDir(fsys, name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	hugepages := make(map[uint64]Hugepages)
+	for _, i := range dir {
+		if !i.IsDir() {
+			continue
+		}
+
+		// hugepages-2048kB
+		if !strings.HasPrefix(i.Name(), "hugepages-") || !strings.HasSuffix(i.Name(), "kB") {
+			continue
+		}
+
+		size, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(i.Name(), "hugepages-"), "kB"), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("convert page size %q: %w", i.Name(), err)
+		}
+
+		sizePath := path.Join(name, i.Name())
+
+		var h Hugepages
+		if h.Total, err = readUint64(fsys, path.Join(sizePath, "nr_hugepages")); err != nil {
+			return nil, err
+		}
+		if h.Free, err = readUint64(fsys, path.Join(sizePath, "free_hugepages")); err != nil {
+			return nil, err
+		}
+		if h.Surplus, err = readUint64(fsys, path.Join(sizePath, "surplus_hugepages")); err != nil {
+			return nil, err
+		}
+
+		hugepages[size*1024] = h
+	}
+
+	return hugepages, nil
+}
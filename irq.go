@@ -0,0 +1,116 @@
+package numa
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// IRQ represent interrupt and CPUs allowed to service it.
+type IRQ struct {
+	Number int
+	// Description is interrupt chip, hardware IRQ and device names
+	// as listed in /proc/interrupts.
+	Description string
+	// Counts are interrupts serviced by each CPU indexed by CPU column.
+	Counts   []uint64
+	Affinity []int
+	// Nodes are nodes of Affinity CPUs.
+	Nodes NodeSet
+}
+
+// GetIRQs returns device interrupts with their CPU affinity.
+func GetIRQs(opts ...Option) ([]IRQ, error) {
+	o := newOptions(opts)
+
+	cpuNodes, err := cpuNodeMap(o)
+	if err != nil {
+		return nil, fmt.Errorf("read cpu nodes: %w", err)
+	}
+
+	f, err := o.proc.Open("interrupts")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var irqs []IRQ
+	var cpus int
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		//            CPU0       CPU1
+		if strings.HasPrefix(fields[0], "CPU") {
+			cpus = len(fields)
+			continue
+		}
+
+		//  28:          0          5  PCI-MSIX-0000:00:01.0   0-edge      virtio0-config
+		number, err := strconv.Atoi(strings.TrimSuffix(fields[0], ":"))
+		if err != nil {
+			// NMI, LOC and other architecture specific interrupts.
+			continue
+		}
+
+		irq := IRQ{Number: number}
+		for _, c := range fields[1:] {
+			if len(irq.Counts) == cpus {
+				break
+			}
+			count, err := strconv.ParseUint(c, 10, 64)
+			if err != nil {
+				break
+			}
+			irq.Counts = append(irq.Counts, count)
+		}
+		irq.Description = strings.Join(fields[1+len(irq.Counts):], " ")
+
+		affinity, err := fs.ReadFile(o.proc, path.Join("irq", strconv.Itoa(number), "smp_affinity_list"))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if irq.Affinity, err = parseList(string(affinity)); err != nil {
+			return nil, fmt.Errorf("parse irq %d affinity: %w", number, err)
+		}
+
+		for _, cpu := range irq.Affinity {
+			if node, ok := cpuNodes[cpu]; ok {
+				irq.Nodes.Add(node)
+			}
+		}
+
+		irqs = append(irqs, irq)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return irqs, nil
+}
+
+// GetIRQsByNode returns device interrupts keyed by nodes whose CPUs service them.
+// Interrupt with affinity spanning several nodes is listed under each of them.
+func GetIRQsByNode(opts ...Option) (map[int][]IRQ, error) {
+	irqs, err := GetIRQs(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	byNode := make(map[int][]IRQ)
+	for _, irq := range irqs {
+		for _, node := range irq.Nodes.IDs() {
+			byNode[node] = append(byNode[node], irq)
+		}
+	}
+
+	return byNode, nil
+}
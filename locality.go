@@ -0,0 +1,19 @@
+package numa
+
+// localityScore returns share of memory usage on local nodes.
+// Process without memory is considered local.
+func localityScore(usage map[int]uint64, local NodeSet) float64 {
+	var total, onLocal uint64
+	for node, bytes := range usage {
+		total += bytes
+		if local.Contains(node) {
+			onLocal += bytes
+		}
+	}
+
+	if total == 0 {
+		return 1
+	}
+
+	return float64(onLocal) / float64(total)
+}
@@ -0,0 +1,50 @@
+package numa
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+)
+
+// LocalityScore returns share of process memory, from 0 to 1, residing on
+// nodes of CPUs its threads are allowed to run on by their affinity. Process
+// bound to a single node with all its memory on that node has score 1, process
+// bound to node 0 with all its memory on node 1 has score 0. Pid 0 means
+// the current process.
+func LocalityScore(pid int) (float64, error) {
+	threads, err := GetThreads(pid)
+	if err != nil {
+		return 0, fmt.Errorf("read threads: %w", err)
+	}
+
+	cpuNodes, err := CPUToNodeMap()
+	if err != nil {
+		return 0, fmt.Errorf("read cpu nodes: %w", err)
+	}
+
+	usage, err := ProcessNodeUsage(pid)
+	if err != nil {
+		return 0, fmt.Errorf("read node usage: %w", err)
+	}
+
+	// Affinity is read by sched_getaffinity instead of node thread last ran
+	// on, which changes as scheduler moves threads between allowed CPUs.
+	var local NodeSet
+	for _, t := range threads {
+		cpus, err := GetThreadAffinity(t.TID)
+		if err != nil {
+			// Thread exited meanwhile.
+			if errors.Is(err, syscall.ESRCH) {
+				continue
+			}
+			return 0, fmt.Errorf("thread %d affinity: %w", t.TID, err)
+		}
+		for _, cpu := range cpus.IDs() {
+			if node, ok := cpuNodes[cpu]; ok {
+				local.Add(node)
+			}
+		}
+	}
+
+	return localityScore(usage, local), nil
+}
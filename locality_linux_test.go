@@ -0,0 +1,18 @@
+package numa
+
+import "testing"
+
+func TestLocalityScoreSingleNode(t *testing.T) {
+	if ids, err := NodeIDs(); err != nil || len(ids) != 1 {
+		t.Skipf("test requires single node, nodes %v: %v", ids, err)
+	}
+
+	// All memory and allowed CPUs of the process are on the only node.
+	score, err := LocalityScore(0)
+	if err != nil {
+		t.Skip(err)
+	}
+	if score != 1 {
+		t.Errorf("LocalityScore(0) = %v, want 1", score)
+	}
+}
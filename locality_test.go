@@ -0,0 +1,21 @@
+package numa
+
+import "testing"
+
+func TestLocalityScore(t *testing.T) {
+	tests := []struct {
+		usage map[int]uint64
+		local NodeSet
+		want  float64
+	}{
+		{map[int]uint64{0: 100}, NewNodeSet(0), 1},
+		{map[int]uint64{1: 100}, NewNodeSet(0), 0},
+		{map[int]uint64{0: 30, 2: 10}, NewNodeSet(0, 1), 0.75},
+		{nil, NewNodeSet(0), 1},
+	}
+	for _, tt := range tests {
+		if got := localityScore(tt.usage, tt.local); got != tt.want {
+			t.Errorf("localityScore(%v, %s) = %v, want %v", tt.usage, tt.local, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package numa
+
+import "encoding/json"
+
+// nodeAlias has Node fields without its methods to avoid marshaling recursion.
+type nodeAlias Node
+
+// humanNode represent Node with human-readable memory sizes.
+type humanNode struct {
+	nodeAlias `yaml:",inline"`
+
+	MemAvailableHuman string `json:"mem_available_human,omitempty" yaml:"mem_available_human,omitempty"`
+	MemFreeHuman      string `json:"mem_free_human,omitempty" yaml:"mem_free_human,omitempty"`
+	MemTotalHuman     string `json:"mem_total_human,omitempty" yaml:"mem_total_human,omitempty"`
+
+	// MemUsed and MemUsedPercent are derived on marshaling and ignored on unmarshaling.
+	MemUsed        uint64  `json:"mem_used" yaml:"mem_used"`
+	MemUsedPercent float64 `json:"mem_used_percent" yaml:"mem_used_percent"`
+}
+
+func newHumanNode(n Node) humanNode {
+	return humanNode{
+		nodeAlias:         nodeAlias(n),
+		MemAvailableHuman: FormatBytes(n.MemAvailable),
+		MemFreeHuman:      FormatBytes(n.MemFree),
+		MemTotalHuman:     FormatBytes(n.MemTotal),
+		MemUsed:           n.MemUsed(),
+		MemUsedPercent:    n.MemUsedPercent(),
+	}
+}
+
+// MarshalJSON returns node with memory sizes both in bytes and human-readable,
+// e.g. "mem_total": 270164381696 and "mem_total_human": "251.6 GiB".
+func (n Node) MarshalJSON() ([]byte, error) {
+	return json.Marshal(newHumanNode(n))
+}
+
+// UnmarshalJSON parses node returned by MarshalJSON. Human-readable sizes
+// are used only if byte values are missing, e.g. in hand-written snapshots.
+func (n *Node) UnmarshalJSON(data []byte) error {
+	var h humanNode
+	if err := json.Unmarshal(data, &h); err != nil {
+		return err
+	}
+
+	for _, s := range []struct {
+		bytes *uint64
+		human string
+	}{
+		{&h.MemAvailable, h.MemAvailableHuman},
+		{&h.MemFree, h.MemFreeHuman},
+		{&h.MemTotal, h.MemTotalHuman},
+	} {
+		if *s.bytes != 0 || s.human == "" {
+			continue
+		}
+
+		v, err := ParseBytes(s.human)
+		if err != nil {
+			return err
+		}
+		*s.bytes = v
+	}
+
+	*n = Node(h.nodeAlias)
+	return nil
+}
+
+// MarshalYAML returns node with memory sizes both in bytes and human-readable
+// for gopkg.in/yaml encoders.
+func (n Node) MarshalYAML() (interface{}, error) {
+	return newHumanNode(n), nil
+}
@@ -0,0 +1,96 @@
+package numa
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// CgroupNumaStat represent per-node memory usage of memory cgroup in bytes.
+type CgroupNumaStat struct {
+	Anon        map[int]uint64
+	File        map[int]uint64
+	Unevictable map[int]uint64
+	// Raw contains every memory.numa_stat line keyed by name, e.g. "total"
+	// or "hierarchical_anon" in cgroup v1 and "shmem" or "slab" in cgroup v2.
+	Raw map[string]map[int]uint64
+}
+
+// GetCgroupNumaStat returns per-node memory usage of the current process
+// memory cgroup, cgroup v2 is used if it has memory controller, cgroup v1 otherwise.
+func GetCgroupNumaStat() (CgroupNumaStat, error) {
+	if dir, mountPoint, err := cgroupV2Dir(0); err == nil && dir != mountPoint {
+		stat, err := ParseCgroupNumaStat(dir)
+		if err == nil || !os.IsNotExist(err) {
+			return stat, err
+		}
+	}
+
+	dir, err := cgroupV1Dir(0, "memory")
+	if err != nil {
+		return CgroupNumaStat{}, err
+	}
+
+	return ParseCgroupNumaStat(dir)
+}
+
+// ParseCgroupNumaStat returns per-node memory usage of memory cgroup directory,
+// e.g. "/sys/fs/cgroup/kubepods.slice". Both cgroup v1 and v2 formats are supported.
+func ParseCgroupNumaStat(dir string) (CgroupNumaStat, error) {
+	f, err := os.Open(filepath.Join(dir, "memory.numa_stat"))
+	if err != nil {
+		return CgroupNumaStat{}, err
+	}
+	defer f.Close()
+
+	raw := make(map[string]map[int]uint64)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		// cgroup v1 counts pages: total=17719 N0=17719
+		// cgroup v2 counts bytes: anon N0=72597504
+		name, _, v1 := strings.Cut(fields[0], "=")
+		multiplier := uint64(1)
+		if v1 {
+			multiplier = uint64(os.Getpagesize())
+		}
+
+		nodes := make(map[int]uint64)
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok || !strings.HasPrefix(key, "N") {
+				continue
+			}
+
+			node, err := strconv.Atoi(strings.TrimPrefix(key, "N"))
+			if err != nil {
+				return CgroupNumaStat{}, fmt.Errorf("convert node %q: %w", key, err)
+			}
+
+			v, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return CgroupNumaStat{}, fmt.Errorf("convert %s %q: %w", name, value, err)
+			}
+			nodes[node] = v * multiplier
+		}
+		raw[name] = nodes
+	}
+
+	if err := scanner.Err(); err != nil {
+		return CgroupNumaStat{}, err
+	}
+
+	return CgroupNumaStat{
+		Anon:        raw["anon"],
+		File:        raw["file"],
+		Unevictable: raw["unevictable"],
+		Raw:         raw,
+	}, nil
+}
@@ -0,0 +1,202 @@
+package numa
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// MemInfo represent NUMA node meminfo in bytes.
+// HugePages_* values are in pages as reported by kernel.
+type MemInfo struct {
+	MemTotal       uint64 `json:"mem_total" yaml:"mem_total"`
+	MemFree        uint64 `json:"mem_free" yaml:"mem_free"`
+	MemUsed        uint64 `json:"mem_used" yaml:"mem_used"`
+	SwapCached     uint64 `json:"swap_cached" yaml:"swap_cached"`
+	Active         uint64 `json:"active" yaml:"active"`
+	Inactive       uint64 `json:"inactive" yaml:"inactive"`
+	ActiveAnon     uint64 `json:"active_anon" yaml:"active_anon"`
+	InactiveAnon   uint64 `json:"inactive_anon" yaml:"inactive_anon"`
+	ActiveFile     uint64 `json:"active_file" yaml:"active_file"`
+	InactiveFile   uint64 `json:"inactive_file" yaml:"inactive_file"`
+	Unevictable    uint64 `json:"unevictable" yaml:"unevictable"`
+	Mlocked        uint64 `json:"mlocked" yaml:"mlocked"`
+	Dirty          uint64 `json:"dirty" yaml:"dirty"`
+	Writeback      uint64 `json:"writeback" yaml:"writeback"`
+	FilePages      uint64 `json:"file_pages" yaml:"file_pages"`
+	Mapped         uint64 `json:"mapped" yaml:"mapped"`
+	AnonPages      uint64 `json:"anon_pages" yaml:"anon_pages"`
+	Shmem          uint64 `json:"shmem" yaml:"shmem"`
+	KernelStack    uint64 `json:"kernel_stack" yaml:"kernel_stack"`
+	PageTables     uint64 `json:"page_tables" yaml:"page_tables"`
+	KReclaimable   uint64 `json:"kreclaimable" yaml:"kreclaimable"`
+	Slab           uint64 `json:"slab" yaml:"slab"`
+	SReclaimable   uint64 `json:"sreclaimable" yaml:"sreclaimable"`
+	SUnreclaim     uint64 `json:"sunreclaim" yaml:"sunreclaim"`
+	AnonHugePages  uint64 `json:"anon_huge_pages" yaml:"anon_huge_pages"`
+	ShmemHugePages uint64 `json:"shmem_huge_pages" yaml:"shmem_huge_pages"`
+	FileHugePages  uint64 `json:"file_huge_pages" yaml:"file_huge_pages"`
+	HugePagesTotal uint64 `json:"huge_pages_total" yaml:"huge_pages_total"`
+	HugePagesFree  uint64 `json:"huge_pages_free" yaml:"huge_pages_free"`
+	HugePagesSurp  uint64 `json:"huge_pages_surp" yaml:"huge_pages_surp"`
+	// Raw contains every meminfo line keyed by name as it appears in file.
+	Raw map[string]uint64 `json:"raw" yaml:"raw"`
+}
+
+// memInfoKeys are meminfo keys having MemInfo fields.
+var memInfoKeys = map[string]bool{
+	"MemTotal": true, "MemFree": true, "MemUsed": true, "SwapCached": true,
+	"Active": true, "Inactive": true, "Active(anon)": true, "Inactive(anon)": true,
+	"Active(file)": true, "Inactive(file)": true, "Unevictable": true, "Mlocked": true,
+	"Dirty": true, "Writeback": true, "FilePages": true, "Mapped": true,
+	"AnonPages": true, "Shmem": true, "KernelStack": true, "PageTables": true,
+	"KReclaimable": true, "Slab": true, "SReclaimable": true, "SUnreclaim": true,
+	"AnonHugePages": true, "ShmemHugePages": true, "FileHugePages": true,
+	"HugePages_Total": true, "HugePages_Free": true, "HugePages_Surp": true,
+}
+
+// parseMemInfo parses node meminfo or system-wide /proc/meminfo.
+func parseMemInfo(o options, fsys fs.FS, name string) (MemInfo, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return MemInfo{}, err
+	}
+	defer f.Close()
+
+	raw := make(map[string]uint64)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// Node 0 MemTotal:       263777956 kB
+		// MemTotal:       263777956 kB
+		tokens := strings.Split(scanner.Text(), ":")
+		if len(tokens) != 2 {
+			if err := o.malformedLine(name, scanner.Text()); err != nil {
+				return MemInfo{}, err
+			}
+			continue
+		}
+
+		keyTokens := strings.Fields(tokens[0])
+		if len(keyTokens) != 3 && len(keyTokens) != 1 {
+			if err := o.malformedLine(name, scanner.Text()); err != nil {
+				return MemInfo{}, err
+			}
+			continue
+		}
+		key := keyTokens[len(keyTokens)-1]
+		if !memInfoKeys[key] {
+			o.debug("unknown meminfo key, it's kept only in Raw", "file", name, "key", key)
+		}
+
+		value := strings.TrimSpace(tokens[1])
+		multiplier := uint64(1)
+		if strings.HasSuffix(value, " kB") {
+			value = strings.TrimSpace(strings.TrimSuffix(value, " kB"))
+			multiplier = 1024
+		}
+
+		t, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			if err := o.malformedValue(name, fmt.Errorf("convert %s %q: %w", key, value, err)); err != nil {
+				return MemInfo{}, err
+			}
+			continue
+		}
+		raw[key] = t * multiplier
+	}
+
+	if err := scanner.Err(); err != nil {
+		return MemInfo{}, err
+	}
+
+	return newMemInfo(raw), nil
+}
+
+// newMemInfo returns MemInfo of meminfo values keyed by name.
+func newMemInfo(raw map[string]uint64) MemInfo {
+	return MemInfo{
+		MemTotal:       raw["MemTotal"],
+		MemFree:        raw["MemFree"],
+		MemUsed:        raw["MemUsed"],
+		SwapCached:     raw["SwapCached"],
+		Active:         raw["Active"],
+		Inactive:       raw["Inactive"],
+		ActiveAnon:     raw["Active(anon)"],
+		InactiveAnon:   raw["Inactive(anon)"],
+		ActiveFile:     raw["Active(file)"],
+		InactiveFile:   raw["Inactive(file)"],
+		Unevictable:    raw["Unevictable"],
+		Mlocked:        raw["Mlocked"],
+		Dirty:          raw["Dirty"],
+		Writeback:      raw["Writeback"],
+		FilePages:      raw["FilePages"],
+		Mapped:         raw["Mapped"],
+		AnonPages:      raw["AnonPages"],
+		Shmem:          raw["Shmem"],
+		KernelStack:    raw["KernelStack"],
+		PageTables:     raw["PageTables"],
+		KReclaimable:   raw["KReclaimable"],
+		Slab:           raw["Slab"],
+		SReclaimable:   raw["SReclaimable"],
+		SUnreclaim:     raw["SUnreclaim"],
+		AnonHugePages:  raw["AnonHugePages"],
+		ShmemHugePages: raw["ShmemHugePages"],
+		FileHugePages:  raw["FileHugePages"],
+		HugePagesTotal: raw["HugePages_Total"],
+		HugePagesFree:  raw["HugePages_Free"],
+		HugePagesSurp:  raw["HugePages_Surp"],
+		Raw:            raw,
+	}
+}
+
+// readNodeMemInfo returns node meminfo. If it can't be read and fallback is
+// enabled by WithMemInfoFallback, values of /proc/meminfo divided equally
+// between nodes with memory are returned and approximate is true.
+func readNodeMemInfo(o options, nodePath string) (m MemInfo, approximate bool, err error) {
+	m, err = parseMemInfo(o, o.sys, path.Join(nodePath, "meminfo"))
+	if err == nil || !o.memInfoFallback {
+		return m, false, err
+	}
+
+	system, sysErr := parseMemInfo(o, o.proc, "meminfo")
+	if sysErr != nil {
+		return MemInfo{}, false, fmt.Errorf("%w, parse /proc/meminfo: %v", err, sysErr)
+	}
+
+	nodes, stateErr := readNodeState(o, "has_memory")
+	if stateErr != nil {
+		return MemInfo{}, false, fmt.Errorf("%w, read memory nodes: %v", err, stateErr)
+	}
+	count := uint64(max(nodes.Len(), 1))
+
+	o.warn("node meminfo not readable, estimated from /proc/meminfo", "path", nodePath, "err", err)
+
+	raw := make(map[string]uint64, len(system.Raw))
+	for k, v := range system.Raw {
+		raw[k] = v / count
+	}
+
+	return newMemInfo(raw), true, nil
+}
+
+// MemUsed returns node memory in bytes which can't be made available without
+// swapping, MemTotal minus MemAvailable. Unlike MemInfo.MemUsed reported by
+// kernel as MemTotal minus MemFree it doesn't count reclaimable page cache.
+func (n Node) MemUsed() uint64 {
+	if n.MemAvailable >= n.MemTotal {
+		return 0
+	}
+	return n.MemTotal - n.MemAvailable
+}
+
+// MemUsedPercent returns MemUsed as percentage of MemTotal from 0 to 100,
+// 0 for nodes without memory.
+func (n Node) MemUsedPercent() float64 {
+	if n.MemTotal == 0 {
+		return 0
+	}
+	return float64(n.MemUsed()) / float64(n.MemTotal) * 100
+}
@@ -0,0 +1,68 @@
+package numa
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// MemorySideCache represent memory side cache of NUMA node, e.g. HBM or DRAM
+// caching slower memory.
+type MemorySideCache struct {
+	Level    int    `json:"level" yaml:"level"`
+	Size     uint64 `json:"size" yaml:"size"`
+	LineSize uint64 `json:"line_size" yaml:"line_size"`
+	// Indexing is 0 for direct mapped cache and non-zero for indexed cache.
+	Indexing uint64 `json:"indexing" yaml:"indexing"`
+	// WritePolicy is 0 for write-back and non-zero for write-through cache.
+	WritePolicy uint64 `json:"write_policy" yaml:"write_policy"`
+}
+
+func parseMemorySideCaches(fsys fs.FS, name string) ([]MemorySideCache, error) {
+	dir, err := fs.ReadDir(fsys, name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var caches []MemorySideCache
+	for _, i := range dir {
+		if !i.IsDir() {
+			continue
+		}
+
+		if !strings.HasPrefix(i.Name(), "index") {
+			continue
+		}
+
+		level, err := strconv.Atoi(strings.TrimPrefix(i.Name(), "index"))
+		if err != nil {
+			return nil, fmt.Errorf("convert level %q: %w", i.Name(), err)
+		}
+
+		indexPath := path.Join(name, i.Name())
+
+		c := MemorySideCache{Level: level}
+		if c.Size, err = readUint64(fsys, path.Join(indexPath, "size")); err != nil {
+			return nil, err
+		}
+		if c.LineSize, err = readUint64(fsys, path.Join(indexPath, "line_size")); err != nil {
+			return nil, err
+		}
+		if c.Indexing, err = readUint64(fsys, path.Join(indexPath, "indexing")); err != nil {
+			return nil, err
+		}
+		if c.WritePolicy, err = readUint64(fsys, path.Join(indexPath, "write_policy")); err != nil {
+			return nil, err
+		}
+
+		caches = append(caches, c)
+	}
+
+	return caches, nil
+}
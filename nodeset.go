@@ -0,0 +1,130 @@
+package numa
+
+import "math/bits"
+
+// NodeSet represent set of NUMA node IDs.
+type NodeSet struct {
+	mask bitmask
+}
+
+// NewNodeSet returns NodeSet containing given node IDs.
+func NewNodeSet(ids ...int) NodeSet {
+	var s NodeSet
+	for _, id := range ids {
+		if id >= 0 {
+			s.mask.set(id)
+		}
+	}
+	return s
+}
+
+// ParseNodeSet parses node list in kernel list format, e.g. "0-1,3".
+func ParseNodeSet(list string) (NodeSet, error) {
+	ids, err := parseList(list)
+	if err != nil {
+		return NodeSet{}, err
+	}
+	return NewNodeSet(ids...), nil
+}
+
+// Add adds node ID to the set. Negative IDs are ignored.
+func (s *NodeSet) Add(id int) {
+	if id < 0 {
+		return
+	}
+	// The mask could be shared with copies of the set.
+	s.mask = s.mask.with(id)
+}
+
+// Contains reports whether node ID is in the set.
+func (s NodeSet) Contains(id int) bool {
+	return s.mask.isSet(id)
+}
+
+// Len returns number of nodes in the set.
+func (s NodeSet) Len() int {
+	return s.mask.count()
+}
+
+// IDs returns node IDs in ascending order.
+func (s NodeSet) IDs() []int {
+	return s.mask.ids()
+}
+
+// String returns set in kernel list format.
+func (s NodeSet) String() string {
+	return s.mask.list()
+}
+
+// MarshalText returns set in kernel list format, e.g. "0-1,3".
+func (s NodeSet) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText parses set in kernel list format.
+func (s *NodeSet) UnmarshalText(text []byte) error {
+	parsed, err := ParseNodeSet(string(text))
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
+
+// NodeSetFromUintptrs returns NodeSet from nodemask words filled by mempolicy
+// system calls, e.g. get_mempolicy.
+func NodeSetFromUintptrs(words []uintptr) NodeSet {
+	var s NodeSet
+	for word, w := range words {
+		for w != 0 {
+			bit := bits.TrailingZeros64(uint64(w))
+			s.Add(word*bits.UintSize + bit)
+			w &^= 1 << uint(bit)
+		}
+	}
+	return s
+}
+
+// Remove removes node ID from the set.
+func (s *NodeSet) Remove(id int) {
+	if !s.mask.isSet(id) {
+		return
+	}
+	// The mask could be shared with copies of the set.
+	s.mask = s.mask.difference(NewNodeSet(id).mask)
+}
+
+// Union returns set of nodes in either set.
+func (s NodeSet) Union(other NodeSet) NodeSet {
+	return NodeSet{mask: s.mask.union(other.mask)}
+}
+
+// Intersect returns set of nodes in both sets.
+func (s NodeSet) Intersect(other NodeSet) NodeSet {
+	return NodeSet{mask: s.mask.intersect(other.mask)}
+}
+
+// Difference returns set of nodes in s but not in other.
+func (s NodeSet) Difference(other NodeSet) NodeSet {
+	return NodeSet{mask: s.mask.difference(other.mask)}
+}
+
+// Equal reports whether sets contain the same nodes.
+func (s NodeSet) Equal(other NodeSet) bool {
+	return s.mask.equal(other.mask)
+}
+
+// Uintptrs returns set as nodemask words expected by mempolicy system calls,
+// e.g. set_mempolicy, mbind and migrate_pages. Node N is bit N%W of word N/W
+// where W is bits.UintSize.
+func (s NodeSet) Uintptrs() []uintptr {
+	var words []uintptr
+	for _, id := range s.mask.ids() {
+		word := id / bits.UintSize
+		for len(words) <= word {
+			words = append(words, 0)
+		}
+		words[word] |= 1 << (uint(id) % bits.UintSize)
+	}
+	return words
+}
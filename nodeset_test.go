@@ -0,0 +1,30 @@
+package numa
+
+import "testing"
+
+func TestNodeSetAddCopy(t *testing.T) {
+	a := NewNodeSet(1, 2)
+	b := a
+	b.Add(3)
+	b.Add(200)
+
+	if got := a.String(); got != "1-2" {
+		t.Errorf("original set = %q, want %q", got, "1-2")
+	}
+	if got := b.String(); got != "1-3,200" {
+		t.Errorf("copy = %q, want %q", got, "1-3,200")
+	}
+}
+
+func TestNodeSetRemoveCopy(t *testing.T) {
+	a := NewNodeSet(1, 2)
+	b := a
+	b.Remove(1)
+
+	if got := a.String(); got != "1-2" {
+		t.Errorf("original set = %q, want %q", got, "1-2")
+	}
+	if got := b.String(); got != "2" {
+		t.Errorf("copy = %q, want %q", got, "2")
+	}
+}
@@ -2,209 +2,454 @@ package numa
 
 import (
 	"bufio"
+	"context"
 	"fmt"
+	"io/fs"
+	"iter"
 	"math"
 	"os"
-	"path/filepath"
+	"path"
+	"slices"
 	"strconv"
 	"strings"
 )
 
-// Node represent NUMA node ID, CPU IDs and memory information.
+// Node represent NUMA node ID, CPU IDs, memory information and statistics.
 type Node struct {
-	ID           int
-	CPU          []int
-	MemAvailable uint64
-	MemFree      uint64
-	MemTotal     uint64
+	ID           int    `json:"id" yaml:"id"`
+	CPU          []int  `json:"cpu" yaml:"cpu"`
+	MemAvailable uint64 `json:"mem_available" yaml:"mem_available"`
+	MemFree      uint64 `json:"mem_free" yaml:"mem_free"`
+	MemTotal     uint64 `json:"mem_total" yaml:"mem_total"`
+	// Distances to other nodes in order of node IDs, see Distance.
+	Distances []int `json:"distances" yaml:"distances"`
+	// DistanceNodes are IDs of nodes of Distances, i.e. online nodes. Distances
+	// are indexed by node ID if it's empty, e.g. for nodes of other providers.
+	DistanceNodes []int `json:"distance_nodes,omitempty" yaml:"distance_nodes,omitempty"`
+	// Hugepages keyed by page size in bytes.
+	Hugepages map[uint64]Hugepages `json:"hugepages" yaml:"hugepages"`
+	Stats     NodeStats            `json:"stats" yaml:"stats"`
+	MemInfo   MemInfo              `json:"meminfo" yaml:"meminfo"`
+	// MemApproximate is set when memory is estimated from system-wide
+	// /proc/meminfo, see WithMemInfoFallback.
+	MemApproximate bool `json:"mem_approximate" yaml:"mem_approximate"`
+
+	MemorySideCaches []MemorySideCache  `json:"memory_side_caches" yaml:"memory_side_caches"`
+	Access           []AccessAttributes `json:"access" yaml:"access"`
+	// MemoryTier is -1 if the node doesn't belong to any memory tier.
+	MemoryTier int    `json:"memory_tier" yaml:"memory_tier"`
+	Zones      []Zone `json:"zones" yaml:"zones"`
+
+	Online          bool `json:"online" yaml:"online"`
+	HasCPU          bool `json:"has_cpu" yaml:"has_cpu"`
+	HasMemory       bool `json:"has_memory" yaml:"has_memory"`
+	HasNormalMemory bool `json:"has_normal_memory" yaml:"has_normal_memory"`
 }
 
-type memInfo struct {
-	MemTotal     uint64
-	MemFree      uint64
-	ActiveFile   uint64
-	InactiveFile uint64
-	SReclaimable uint64
+// GetNodes returns NUMA nodes information.
+// Nodes whose files can't be read are skipped and their errors, see NodeError,
+// are joined into returned error together with successfully read nodes.
+func GetNodes(opts ...Option) ([]Node, error) {
+	return GetNodesContext(context.Background(), opts...)
 }
 
-// GetNodes returns NUMA nodes information.
-func GetNodes() ([]Node, error) {
-	dir, err := os.ReadDir("/sys/devices/system/node/")
-	if err != nil {
-		return nil, err
+// GetNodesContext is like GetNodes but returns context error when ctx is done.
+// Concurrent calls without options share a single read, see Collector.
+func GetNodesContext(ctx context.Context, opts ...Option) ([]Node, error) {
+	if len(opts) == 0 {
+		return defaultCollector.Nodes(ctx)
 	}
+	return NewCollector(opts...).Nodes(ctx)
+}
 
-	var nodes []Node
-	for _, i := range dir {
-		if !i.IsDir() {
-			continue
+// Nodes returns iterator yielding nodes as they are read. Node which can't be
+// read is yielded as NodeError and iteration continues, other errors are
+// yielded once and stop iteration.
+func Nodes(opts ...Option) iter.Seq2[Node, error] {
+	return nodesSeq(context.Background(), newOptions(opts))
+}
+
+func nodesSeq(ctx context.Context, o options) iter.Seq2[Node, error] {
+	return func(yield func(Node, error) bool) {
+		if nodes, ok, err := providerNodes(ctx, o); ok {
+			for _, n := range nodes {
+				if !yield(n, nil) {
+					return
+				}
+			}
+			if err != nil {
+				yield(Node{}, err)
+			}
+			return
 		}
 
-		if !strings.HasPrefix(i.Name(), "node") {
-			continue
+		ids, err := sysfsNodeIDs(o)
+		if err != nil {
+			yield(Node{}, noNUMAErr(err))
+			return
 		}
 
-		nodeID, err := strconv.Atoi(strings.TrimPrefix(i.Name(), "node"))
+		shared, err := readSharedInfo(o)
 		if err != nil {
-			return nil, err
+			yield(Node{}, err)
+			return
 		}
 
-		nodePath := filepath.Join("/sys/devices/system/node", i.Name())
+		var cpuset cgroupCpuset
+		if o.cgroupFiltering {
+			if cpuset, err = readCgroupCpuset(); err != nil {
+				yield(Node{}, err)
+				return
+			}
+		}
 
-		meminfo, err := parseMemInfo(filepath.Join(nodePath, "meminfo"))
-		if err != nil {
-			return nil, fmt.Errorf("parse meminfo: %w", err)
+		for _, nodeID := range ids {
+			if err := ctx.Err(); err != nil {
+				yield(Node{}, err)
+				return
+			}
+
+			n, err := readNode(o, shared, nodeID)
+			if err != nil {
+				o.warn("skip node", "node", nodeID, "err", err)
+				if !yield(Node{}, &NodeError{Node: nodeID, Err: err}) {
+					return
+				}
+				continue
+			}
+
+			if o.cgroupFiltering {
+				var ok bool
+				if n, ok = cpuset.filter(n); !ok {
+					o.debug("skip node outside of cpuset cgroup", "node", nodeID)
+					continue
+				}
+			}
+
+			if !yield(n, nil) {
+				return
+			}
 		}
+	}
+}
 
-		cpuIDs, err := parseCpuList(filepath.Join(nodePath, "cpulist"))
-		if err != nil {
-			return nil, fmt.Errorf("parse cpulist: %w", err)
+// GetNode returns information of single NUMA node reading only its directory
+// and system-wide node states, zoneinfo and memory tiers.
+func GetNode(id int, opts ...Option) (Node, error) {
+	return NewCollector(opts...).Node(id)
+}
+
+// sharedInfo represent system-wide information needed to read every node.
+type sharedInfo struct {
+	states nodeStates
+	zones  []Zone
+	// Without zoneinfo available memory is estimated without watermarks.
+	zonesErr error
+	tiers    []MemoryTier
+	// distanceNodes are online node IDs, kernel writes distance to every online node.
+	distanceNodes []int
+}
+
+func readSharedInfo(o options) (sharedInfo, error) {
+	var s sharedInfo
+	var err error
+
+	if o.fields.has(FieldStates) {
+		if s.states, err = readNodeStates(o); err != nil {
+			return sharedInfo{}, fmt.Errorf("read node states: %w", err)
+		}
+	}
+
+	if o.fields.has(FieldZones) {
+		s.zones, s.zonesErr = readZoneInfo(o)
+		if s.zonesErr != nil {
+			o.warn("zoneinfo not readable, available memory estimated without watermarks", "err", s.zonesErr)
 		}
+	} else {
+		s.zonesErr = errNotRead
+	}
+
+	if o.fields.has(FieldMemoryTier) {
+		if s.tiers, err = readMemoryTiers(o); err != nil {
+			return sharedInfo{}, fmt.Errorf("read memory tiers: %w", err)
+		}
+	}
 
-		nodes = append(nodes, Node{
-			ID:           nodeID,
-			CPU:          cpuIDs,
-			MemAvailable: calculateAvailableMemory(meminfo),
-			MemFree:      meminfo.MemFree,
-			MemTotal:     meminfo.MemTotal,
-		})
+	if o.fields.has(FieldDistances) {
+		s.distanceNodes, err = distanceNodes(o)
+		if err != nil {
+			o.debug("online nodes not readable, distances indexed by node ID", "err", err)
+		}
 	}
 
-	return nodes, nil
+	return s, nil
 }
 
-func parseMemInfo(path string) (memInfo, error) {
-	f, err := os.Open(path)
+// readNode returns node information from its directory and system-wide information.
+func readNode(o options, shared sharedInfo, nodeID int) (Node, error) {
+	n, err := parseNode(o, nodeID, path.Join("devices/system/node", fmt.Sprintf("node%d", nodeID)))
 	if err != nil {
-		return memInfo{}, err
+		return Node{}, err
 	}
 
-	var m memInfo
-	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
-		// Node 0 MemTotal:       263777956 kB
-		tokens := strings.Split(scanner.Text(), ":")
-		if len(tokens) != 2 {
-			continue
+	zonesOfNode := nodeZones(shared.zones, nodeID)
+	if o.fields.has(FieldMemInfo) {
+		n.MemAvailable = nodeAvailableMemory(n.MemInfo, n.MemApproximate, zonesOfNode, shared.zonesErr)
+	}
+	n.Zones = zonesOfNode
+	n.MemoryTier = memoryTierOf(shared.tiers, nodeID)
+	if len(shared.distanceNodes) == len(n.Distances) {
+		n.DistanceNodes = slices.Clone(shared.distanceNodes)
+	}
+
+	n.Online = shared.states.online.Contains(nodeID)
+	n.HasCPU = shared.states.hasCPU.Contains(nodeID)
+	n.HasMemory = shared.states.hasMemory.Contains(nodeID)
+	n.HasNormalMemory = shared.states.hasNormalMemory.Contains(nodeID)
+
+	return n, nil
+}
+
+// NodeError represent error of reading single node files.
+type NodeError struct {
+	Node int
+	Err  error
+}
+
+func (e *NodeError) Error() string {
+	return fmt.Sprintf("node%d: %v", e.Node, e.Err)
+}
+
+func (e *NodeError) Unwrap() error {
+	return e.Err
+}
+
+// parseNode returns node information from files of node directory.
+func parseNode(o options, nodeID int, nodePath string) (Node, error) {
+	n := Node{ID: nodeID}
+	var err error
+
+	if o.fields.has(FieldMemInfo) {
+		if n.MemInfo, n.MemApproximate, err = readNodeMemInfo(o, nodePath); err != nil {
+			return Node{}, fmt.Errorf("parse meminfo: %w", err)
 		}
+		n.MemTotal = n.MemInfo.MemTotal
+		n.MemFree = n.MemInfo.MemFree
+	}
 
-		keyTokens := strings.Split(strings.TrimSpace(tokens[0]), " ")
-		if len(keyTokens) != 3 {
-			continue
+	if n.CPU, err = parseCpuList(o.sys, path.Join(nodePath, "cpulist")); err != nil {
+		var mapErr error
+		n.CPU, mapErr = parseCpuMap(o.sys, path.Join(nodePath, "cpumap"))
+		if mapErr != nil {
+			return Node{}, fmt.Errorf("parse cpulist: %w, parse cpumap: %v", err, mapErr)
 		}
-		key := keyTokens[2]
-		value := strings.Replace(strings.TrimSpace(tokens[1]), " kB", "", -1)
+		o.debug("cpulist not readable, cpumap used", "node", nodeID, "err", err)
+	}
 
-		switch key {
-		case "MemTotal":
-			t, err := strconv.ParseUint(value, 10, 64)
-			if err != nil {
-				return memInfo{}, err
-			}
-			m.MemTotal = t * 1024
-		case "MemFree":
-			t, err := strconv.ParseUint(value, 10, 64)
-			if err != nil {
-				return memInfo{}, err
-			}
-			m.MemFree = t * 1024
-		case "Active(file)":
-			t, err := strconv.ParseUint(value, 10, 64)
-			if err != nil {
-				return memInfo{}, err
-			}
+	if o.fields.has(FieldDistances) {
+		if n.Distances, err = parseDistance(o.sys, path.Join(nodePath, "distance")); err != nil {
+			return Node{}, fmt.Errorf("parse distance: %w", err)
+		}
+	}
 
-			m.ActiveFile = t * 1024
-		case "Inactive(file)":
-			t, err := strconv.ParseUint(value, 10, 64)
-			if err != nil {
-				return memInfo{}, err
-			}
+	if o.fields.has(FieldHugepages) {
+		if n.Hugepages, err = parseHugepages(o.sys, path.Join(nodePath, "hugepages")); err != nil {
+			return Node{}, fmt.Errorf("parse hugepages: %w", err)
+		}
+	}
 
-			m.InactiveFile = t * 1024
-		case "SReclaimable":
-			t, err := strconv.ParseUint(value, 10, 64)
-			if err != nil {
-				return memInfo{}, err
+	if o.fields.has(FieldStats) {
+		if n.Stats, err = parseNumaStat(o, path.Join(nodePath, "numastat")); err != nil {
+			return Node{}, fmt.Errorf("parse numastat: %w", err)
+		}
+	}
+
+	if o.fields.has(FieldMemorySideCaches) {
+		if n.MemorySideCaches, err = parseMemorySideCaches(o.sys, path.Join(nodePath, "memory_side_cache")); err != nil {
+			return Node{}, fmt.Errorf("parse memory side cache: %w", err)
+		}
+	}
+
+	if o.fields.has(FieldAccess) {
+		if n.Access, err = parseAccessAttributes(o.sys, nodePath); err != nil {
+			return Node{}, fmt.Errorf("parse access attributes: %w", err)
+		}
+	}
+
+	return n, nil
+}
+
+// CPUToNodeMap returns node ID keyed by CPU ID built in a single pass over
+// node cpulists. The map could be kept by callers needing frequent lookups,
+// see also Cache.NodeForCPU.
+func CPUToNodeMap(opts ...Option) (map[int]int, error) {
+	return cpuNodeMap(newOptions(opts))
+}
+
+// cpuNodeMap returns node ID keyed by CPU ID reading only node cpulists.
+func cpuNodeMap(o options) (map[int]int, error) {
+	cpuNodes := make(map[int]int)
+
+	if nodes, ok, err := providerNodes(context.Background(), o); ok {
+		for _, n := range nodes {
+			for _, cpu := range n.CPU {
+				cpuNodes[cpu] = n.ID
 			}
+		}
+		return cpuNodes, err
+	}
+
+	dir, err := fs.ReadDir(o.sys, "devices/system/node")
+	if err != nil {
+		return nil, noNUMAErr(err)
+	}
+
+	for _, i := range dir {
+		if !i.IsDir() || !strings.HasPrefix(i.Name(), "node") {
+			continue
+		}
 
-			m.SReclaimable = t * 1024
+		nodeID, err := strconv.Atoi(strings.TrimPrefix(i.Name(), "node"))
+		if err != nil {
+			return nil, err
+		}
+
+		cpuIDs, err := parseCpuList(o.sys, path.Join("devices/system/node", i.Name(), "cpulist"))
+		if err != nil {
+			return nil, fmt.Errorf("parse cpulist: %w", err)
+		}
+
+		for _, cpu := range cpuIDs {
+			cpuNodes[cpu] = nodeID
 		}
 	}
 
-	return m, nil
+	return cpuNodes, nil
 }
 
-func parseCpuList(path string) ([]int, error) {
-	f, err := os.ReadFile(path)
+func parseCpuList(fsys fs.FS, name string) ([]int, error) {
+	f, err := fs.ReadFile(fsys, name)
 	if err != nil {
 		return nil, err
 	}
 
-	// 0-31\n
-	tokens := strings.Split(strings.TrimRight(string(f), "\n"), "-")
-	if len(tokens) != 2 {
-		return nil, fmt.Errorf("invalid format: %q", string(f))
+	// 0-15,32-47\n
+	// Memory-only nodes have empty cpulist.
+	ids, err := parseList(string(f))
+	if err != nil {
+		return nil, fmt.Errorf("invalid format %q: %w", string(f), err)
 	}
 
-	first, err := strconv.Atoi(tokens[0])
+	return ids, nil
+}
+
+func parseCpuMap(fsys fs.FS, name string) ([]int, error) {
+	f, err := fs.ReadFile(fsys, name)
 	if err != nil {
-		return nil, fmt.Errorf("convert first %q: %w", tokens[0], err)
+		return nil, err
 	}
 
-	last, err := strconv.Atoi(tokens[1])
+	// 00000000,0000ffff\n
+	mask, err := parseMask(string(f))
 	if err != nil {
-		return nil, fmt.Errorf("convert last %q: %w", tokens[1], err)
+		return nil, fmt.Errorf("invalid format %q: %w", string(f), err)
 	}
 
-	var ids []int
-	for i := first; i <= last; i++ {
-		ids = append(ids, i)
-	}
+	return mask.ids(), nil
+}
 
-	return ids, nil
+// nodeAvailableMemory returns share of system MemAvailable for approximate
+// meminfo, see readNodeMemInfo, and calculated available memory otherwise.
+func nodeAvailableMemory(m MemInfo, approximate bool, zones []Zone, zonesErr error) uint64 {
+	if available, ok := m.Raw["MemAvailable"]; approximate && ok {
+		return available
+	}
+	return calculateAvailableMemory(m, zones, zonesErr)
 }
 
-func calculateAvailableMemory(m memInfo) uint64 {
-	watermarkLow, err := getWatermarkLow()
-	if err != nil {
+func calculateAvailableMemory(m MemInfo, zones []Zone, zonesErr error) uint64 {
+	if zonesErr != nil {
 		return m.MemFree + m.SReclaimable + m.ActiveFile + m.InactiveFile
 	}
 
-	memAvailable := m.MemFree - watermarkLow
+	var watermarkLow uint64
+	for _, z := range zones {
+		watermarkLow += z.Low
+	}
+	watermarkLow *= uint64(os.Getpagesize())
+
+	var memAvailable uint64
+	if m.MemFree > watermarkLow {
+		memAvailable = m.MemFree - watermarkLow
+	}
 	pageCache := m.ActiveFile + m.InactiveFile
 	pageCache -= uint64(math.Min(float64(pageCache/2), float64(watermarkLow)))
 	memAvailable += pageCache
 	memAvailable += m.SReclaimable - uint64(math.Min(float64(m.SReclaimable/2.0), float64(watermarkLow)))
 
-	if memAvailable < 0 {
-		memAvailable = 0
-	}
-
 	return memAvailable
 }
 
-func getWatermarkLow() (uint64, error) {
-	var watermarkLow uint64
-	watermarkLow = 0
+func readUint64(fsys fs.FS, name string) (uint64, error) {
+	f, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return 0, err
+	}
 
-	f, err := os.Open("/proc/zoneinfo")
+	v, err := strconv.ParseUint(strings.TrimSpace(string(f)), 10, 64)
 	if err != nil {
-		return watermarkLow, err
+		return 0, fmt.Errorf("convert %q: %w", name, err)
 	}
 
+	return v, nil
+}
+
+func parseKeyValues(o options, name string) (map[string]uint64, error) {
+	f, err := o.sys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	values := make(map[string]uint64)
 	scanner := bufio.NewScanner(f)
 	for scanner.Scan() {
+		// numa_hit 3284144
 		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			if err := o.malformedLine(name, scanner.Text()); err != nil {
+				return nil, err
+			}
+			continue
+		}
 
-		if strings.HasPrefix(fields[0], "low") {
-			lowValue, err := strconv.ParseUint(fields[1], 10, 64)
-			if err != nil {
-				lowValue = 0
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			if err := o.malformedValue(name, fmt.Errorf("convert %s %q: %w", fields[0], fields[1], err)); err != nil {
+				return nil, err
 			}
-			watermarkLow += lowValue
+			continue
 		}
+		values[fields[0]] = v
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+func readInt(fsys fs.FS, name string) (int, error) {
+	f, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return 0, err
+	}
+
+	v, err := strconv.Atoi(strings.TrimSpace(string(f)))
+	if err != nil {
+		return 0, fmt.Errorf("convert %q: %w", name, err)
 	}
 
-	return watermarkLow * uint64(os.Getpagesize()), nil
+	return v, nil
 }
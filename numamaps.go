@@ -0,0 +1,176 @@
+package numa
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// NumaMap represent memory mapping of process from /proc/<pid>/numa_maps.
+// Page counts are in pages of KernelPageSize.
+type NumaMap struct {
+	Address uint64
+	// Policy is memory policy of the mapping, e.g. "default" or "bind:0-1".
+	Policy string
+	// File is path of the backing file, empty for anonymous mappings.
+	File  string
+	Heap  bool
+	Stack bool
+	Huge  bool
+
+	Anon      uint64
+	Dirty     uint64
+	Mapped    uint64
+	MapMax    uint64
+	Active    uint64
+	Writeback uint64
+	SwapCache uint64
+	// Nodes is number of pages keyed by node ID.
+	Nodes map[int]uint64
+	// KernelPageSize is in bytes.
+	KernelPageSize uint64
+}
+
+// ParseNumaMaps returns memory mappings of process with per-node page counts.
+// Pid 0 means the current process.
+func ParseNumaMaps(pid int) ([]NumaMap, error) {
+	return ParseNumaMapsContext(context.Background(), pid)
+}
+
+// ParseNumaMapsContext is like ParseNumaMaps but stops and returns context error
+// when ctx is done. Processes with huge number of mappings take long to read.
+func ParseNumaMapsContext(ctx context.Context, pid int) ([]NumaMap, error) {
+	f, err := os.Open(procPath(pid, "numa_maps"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var maps []NumaMap
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// Kernel walks page tables of mapping on read, so context is checked on every line.
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		m, err := parseNumaMap(scanner.Text())
+		if err != nil {
+			return nil, err
+		}
+		maps = append(maps, m)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return maps, nil
+}
+
+func parseNumaMap(line string) (NumaMap, error) {
+	// 7fed41a14000 default file=/usr/lib/libc.so.6 mapped=37 mapmax=4 N0=37 kernelpagesize_kB=4
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return NumaMap{}, fmt.Errorf("invalid format: %q", line)
+	}
+
+	address, err := strconv.ParseUint(fields[0], 16, 64)
+	if err != nil {
+		return NumaMap{}, fmt.Errorf("convert address %q: %w", fields[0], err)
+	}
+
+	m := NumaMap{Address: address, Policy: fields[1], Nodes: make(map[int]uint64)}
+	for _, field := range fields[2:] {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			switch key {
+			case "heap":
+				m.Heap = true
+			case "stack":
+				m.Stack = true
+			case "huge":
+				m.Huge = true
+			}
+			continue
+		}
+
+		if key == "file" {
+			m.File = value
+			continue
+		}
+
+		v, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return NumaMap{}, fmt.Errorf("convert %s %q: %w", key, value, err)
+		}
+
+		switch key {
+		case "anon":
+			m.Anon = v
+		case "dirty":
+			m.Dirty = v
+		case "mapped":
+			m.Mapped = v
+		case "mapmax":
+			m.MapMax = v
+		case "active":
+			m.Active = v
+		case "writeback":
+			m.Writeback = v
+		case "swapcache":
+			m.SwapCache = v
+		case "kernelpagesize_kB":
+			m.KernelPageSize = v * 1024
+		default:
+			// N0=37
+			if strings.HasPrefix(key, "N") {
+				node, err := strconv.Atoi(strings.TrimPrefix(key, "N"))
+				if err == nil {
+					m.Nodes[node] = v
+				}
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// procPath returns path of file in /proc/<pid>, pid 0 means the current process.
+func procPath(pid int, name string) string {
+	if pid == 0 {
+		return filepath.Join("/proc/self", name)
+	}
+	return filepath.Join("/proc", strconv.Itoa(pid), name)
+}
+
+// ProcessNodeUsage returns memory of process in bytes keyed by node ID,
+// similar to `numastat -p`. Pid 0 means the current process.
+func ProcessNodeUsage(pid int) (map[int]uint64, error) {
+	return ProcessNodeUsageContext(context.Background(), pid)
+}
+
+// ProcessNodeUsageContext is like ProcessNodeUsage but stops and returns
+// context error when ctx is done.
+func ProcessNodeUsageContext(ctx context.Context, pid int) (map[int]uint64, error) {
+	maps, err := ParseNumaMapsContext(ctx, pid)
+	if err != nil {
+		return nil, err
+	}
+
+	return nodeUsage(maps), nil
+}
+
+func nodeUsage(maps []NumaMap) map[int]uint64 {
+	usage := make(map[int]uint64)
+	for _, m := range maps {
+		for node, pages := range m.Nodes {
+			usage[node] += pages * m.KernelPageSize
+		}
+	}
+	return usage
+}
@@ -0,0 +1,27 @@
+package numa
+
+// NodeStats represent NUMA node allocation counters from numastat.
+type NodeStats struct {
+	NumaHit       uint64 `json:"numa_hit" yaml:"numa_hit"`
+	NumaMiss      uint64 `json:"numa_miss" yaml:"numa_miss"`
+	NumaForeign   uint64 `json:"numa_foreign" yaml:"numa_foreign"`
+	InterleaveHit uint64 `json:"interleave_hit" yaml:"interleave_hit"`
+	LocalNode     uint64 `json:"local_node" yaml:"local_node"`
+	OtherNode     uint64 `json:"other_node" yaml:"other_node"`
+}
+
+func parseNumaStat(o options, name string) (NodeStats, error) {
+	values, err := parseKeyValues(o, name)
+	if err != nil {
+		return NodeStats{}, err
+	}
+
+	return NodeStats{
+		NumaHit:       values["numa_hit"],
+		NumaMiss:      values["numa_miss"],
+		NumaForeign:   values["numa_foreign"],
+		InterleaveHit: values["interleave_hit"],
+		LocalNode:     values["local_node"],
+		OtherNode:     values["other_node"],
+	}, nil
+}
@@ -0,0 +1,233 @@
+package numa
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// Option configures GetNodes and other readers of sysfs and procfs.
+type Option func(*options)
+
+type options struct {
+	cgroupFiltering bool
+	changesOnly     bool
+	fields          Field
+	logger          *slog.Logger
+	parseMode       ParseMode
+	syntheticNode   bool
+	memInfoFallback bool
+	provider        TopologyProvider
+	sys             fs.FS
+	proc            fs.FS
+	// customSys is set when sysfs is given by WithSysRoot or WithSysFS,
+	// it's read instead of operating system API where sysfs isn't available.
+	customSys bool
+	// sysRoot is used to resolve sysfs symlinks which fs.FS doesn't support.
+	sysRoot string
+	// procRoot is used to write procfs files which fs.FS doesn't support.
+	procRoot string
+}
+
+func newOptions(opts []Option) options {
+	o := options{sys: os.DirFS("/sys"), proc: os.DirFS("/proc"), sysRoot: "/sys", procRoot: "/proc", fields: FieldAll}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+func (o options) debug(msg string, args ...any) {
+	if o.logger != nil {
+		o.logger.Debug(msg, args...)
+	}
+}
+
+func (o options) warn(msg string, args ...any) {
+	if o.logger != nil {
+		o.logger.Warn(msg, args...)
+	}
+}
+
+// malformedLine returns error of line not matching format of file in strict
+// mode, otherwise line is logged and skipped.
+func (o options) malformedLine(name, line string) error {
+	if o.parseMode == ParseStrict {
+		return fmt.Errorf("%s: malformed line %q", name, line)
+	}
+	o.warn("skip malformed line", "file", name, "line", line)
+	return nil
+}
+
+// malformedValue returns err of value which can't be parsed unless in lenient
+// mode where value is logged and skipped.
+func (o options) malformedValue(name string, err error) error {
+	if o.parseMode != ParseLenient {
+		return err
+	}
+	o.warn("skip malformed value", "file", name, "err", err)
+	return nil
+}
+
+// sysPath returns path of sysfs file relative to sysfs root, e.g. "class/drm".
+func (o options) sysPath(elem ...string) string {
+	return filepath.Join(append([]string{o.sysRoot}, elem...)...)
+}
+
+// WithCgroupFiltering makes GetNodes report only nodes and CPUs available
+// to the current process cpuset cgroup, e.g. container. Nodes outside of
+// cpuset mems are reported memory-less: their memory, meminfo, numastat,
+// hugepages and zones are cleared and HasMemory is false.
+func WithCgroupFiltering() Option {
+	return func(o *options) {
+		o.cgroupFiltering = true
+	}
+}
+
+// WithChangesOnly makes watchers send snapshots only when topology changed.
+// It doesn't affect GetNodes.
+func WithChangesOnly() Option {
+	return func(o *options) {
+		o.changesOnly = true
+	}
+}
+
+// Field is a set of Node fields read by GetNodes, see WithFields.
+// Node ID and CPUs are always read.
+type Field uint
+
+const (
+	// FieldMemInfo is node meminfo, MemTotal, MemFree and MemAvailable.
+	FieldMemInfo Field = 1 << iota
+	// FieldZones is Zones read from system-wide zoneinfo. Without it
+	// MemAvailable is estimated without zone watermarks.
+	FieldZones
+	FieldDistances
+	FieldHugepages
+	FieldStats
+	FieldMemorySideCaches
+	FieldAccess
+	// FieldMemoryTier is MemoryTier, it's -1 when not read.
+	FieldMemoryTier
+	// FieldStates is Online, HasCPU, HasMemory and HasNormalMemory.
+	FieldStates
+
+	FieldAll = FieldMemInfo | FieldZones | FieldDistances | FieldHugepages | FieldStats |
+		FieldMemorySideCaches | FieldAccess | FieldMemoryTier | FieldStates
+)
+
+func (f Field) has(field Field) bool {
+	return f&field != 0
+}
+
+// errNotRead is zoneinfo error when zones aren't read, see FieldZones.
+var errNotRead = errors.New("not read")
+
+// WithFields makes GetNodes, GetNode and Node.Refresh read only given fields,
+// e.g. WithFields(FieldDistances) when only CPU mapping and distances are
+// needed. Fields not read have zero values.
+func WithFields(fields Field) Option {
+	return func(o *options) {
+		o.fields = fields
+	}
+}
+
+// WithoutFields makes GetNodes, GetNode and Node.Refresh skip given fields,
+// e.g. WithoutFields(FieldMemInfo|FieldZones) to avoid the most expensive reads.
+func WithoutFields(fields Field) Option {
+	return func(o *options) {
+		o.fields &^= fields
+	}
+}
+
+// WithLogger makes GetNodes and other readers log conditions which don't fail
+// reading but could explain missing or estimated data to logger: skipped nodes
+// and malformed lines at Warn level, fallback sources and unknown meminfo keys
+// at Debug level. Nothing is logged by default.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// ParseMode defines handling of malformed sysfs and procfs content, see WithParseMode.
+type ParseMode int
+
+const (
+	// ParseDefault skips lines not matching file format
+	// and fails on values which can't be parsed.
+	ParseDefault ParseMode = iota
+	// ParseStrict fails on any malformed content, e.g. for validation tools.
+	ParseStrict
+	// ParseLenient skips malformed lines and values, e.g. for monitoring
+	// which prefers partial data to none.
+	ParseLenient
+)
+
+// WithParseMode sets handling of malformed content. Skipped content
+// is logged at Warn level, see WithLogger.
+func WithParseMode(mode ParseMode) Option {
+	return func(o *options) {
+		o.parseMode = mode
+	}
+}
+
+// WithSyntheticNode makes GetNodes report single node 0 with all CPUs and
+// total memory of the system on platforms without NUMA support, e.g. darwin,
+// instead of ErrNoNUMA. Free and available memory of the node are zero.
+func WithSyntheticNode() Option {
+	return func(o *options) {
+		o.syntheticNode = true
+	}
+}
+
+// WithMemInfoFallback makes GetNodes estimate memory of node whose meminfo
+// can't be read, e.g. because of permissions, as system-wide /proc/meminfo
+// divided equally between nodes with memory. Such nodes have MemApproximate set.
+func WithMemInfoFallback() Option {
+	return func(o *options) {
+		o.memInfoFallback = true
+	}
+}
+
+// WithSysRoot makes sysfs to be read from root instead of /sys,
+// e.g. host sysfs mounted into container at /host/sys.
+func WithSysRoot(root string) Option {
+	return func(o *options) {
+		o.sys = os.DirFS(root)
+		o.sysRoot = root
+		o.customSys = true
+	}
+}
+
+// WithProcRoot makes system-wide procfs files, e.g. zoneinfo and interrupts,
+// to be read from root instead of /proc. Files of the current process,
+// e.g. its cgroup, are still read from /proc.
+func WithProcRoot(root string) Option {
+	return func(o *options) {
+		o.proc = os.DirFS(root)
+		o.procRoot = root
+	}
+}
+
+// WithSysFS makes sysfs to be read from fsys, e.g. fstest.MapFS or embedded
+// snapshot, with names relative to sysfs root like "devices/system/node/online".
+// GetGPUs and NodeForBlockDevice resolve device symlinks and still read
+// sysfs root set by WithSysRoot.
+func WithSysFS(fsys fs.FS) Option {
+	return func(o *options) {
+		o.sys = fsys
+		o.customSys = true
+	}
+}
+
+// WithProcFS makes system-wide procfs files to be read from fsys
+// with names relative to procfs root like "zoneinfo".
+func WithProcFS(fsys fs.FS) Option {
+	return func(o *options) {
+		o.proc = fsys
+	}
+}
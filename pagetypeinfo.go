@@ -0,0 +1,134 @@
+package numa
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PageTypeInfo represent free pages per migrate type from /proc/pagetypeinfo.
+type PageTypeInfo struct {
+	PageBlockOrder int
+	PagesPerBlock  uint64
+	Free           []PageTypeFree
+	Blocks         []PageTypeBlocks
+}
+
+// PageTypeFree represent free blocks of migrate type in NUMA node zone.
+type PageTypeFree struct {
+	Node int
+	Zone string
+	Type string
+	// Free is number of free blocks indexed by order.
+	Free []uint64
+}
+
+// PageTypeBlocks represent number of page blocks per migrate type in NUMA node zone.
+type PageTypeBlocks struct {
+	Node   int
+	Zone   string
+	Blocks map[string]uint64
+}
+
+// GetPageTypeInfo returns free pages per migrate type and order of every node zone.
+// Reading /proc/pagetypeinfo usually requires root. Malformed lines are
+// handled according to WithParseMode.
+func GetPageTypeInfo(opts ...Option) (PageTypeInfo, error) {
+	o := newOptions(opts)
+
+	f, err := o.proc.Open("pagetypeinfo")
+	if err != nil {
+		return PageTypeInfo{}, err
+	}
+	defer f.Close()
+
+	var info PageTypeInfo
+	var blockTypes []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		fields := strings.Fields(line)
+
+		switch {
+		case strings.HasPrefix(line, "Page block order:"):
+			if len(fields) != 4 {
+				if err := o.malformedLine("pagetypeinfo", line); err != nil {
+					return PageTypeInfo{}, err
+				}
+				continue
+			}
+			if info.PageBlockOrder, err = strconv.Atoi(fields[3]); err != nil {
+				if err := o.malformedValue("pagetypeinfo", fmt.Errorf("convert page block order %q: %w", fields[3], err)); err != nil {
+					return PageTypeInfo{}, err
+				}
+			}
+		case strings.HasPrefix(line, "Pages per block:"):
+			if len(fields) != 4 {
+				if err := o.malformedLine("pagetypeinfo", line); err != nil {
+					return PageTypeInfo{}, err
+				}
+				continue
+			}
+			if info.PagesPerBlock, err = strconv.ParseUint(fields[3], 10, 64); err != nil {
+				if err := o.malformedValue("pagetypeinfo", fmt.Errorf("convert pages per block %q: %w", fields[3], err)); err != nil {
+					return PageTypeInfo{}, err
+				}
+			}
+		case strings.HasPrefix(line, "Number of blocks type"):
+			// Number of blocks type     Unmovable      Movable  Reclaimable
+			blockTypes = fields[4:]
+		case len(fields) > 0 && fields[0] == "Node":
+			if len(fields) < 6 || fields[2] != "zone" {
+				if err := o.malformedLine("pagetypeinfo", line); err != nil {
+					return PageTypeInfo{}, err
+				}
+				continue
+			}
+
+			node, err := strconv.Atoi(strings.TrimSuffix(fields[1], ","))
+			if err != nil {
+				if err := o.malformedValue("pagetypeinfo", fmt.Errorf("convert node %q: %w", fields[1], err)); err != nil {
+					return PageTypeInfo{}, err
+				}
+				continue
+			}
+			zone := strings.TrimSuffix(fields[3], ",")
+
+			// Node    0, zone   Normal, type      Movable      1    889   1871
+			if fields[4] == "type" {
+				free, err := parseUint64s(fields[6:])
+				if err != nil {
+					if err := o.malformedValue("pagetypeinfo", err); err != nil {
+						return PageTypeInfo{}, err
+					}
+					continue
+				}
+				info.Free = append(info.Free, PageTypeFree{Node: node, Zone: zone, Type: fields[5], Free: free})
+				continue
+			}
+
+			// Node 0, zone   Normal           54          570           16
+			counts, err := parseUint64s(fields[4:])
+			if err != nil {
+				if err := o.malformedValue("pagetypeinfo", err); err != nil {
+					return PageTypeInfo{}, err
+				}
+				continue
+			}
+			blocks := PageTypeBlocks{Node: node, Zone: zone, Blocks: make(map[string]uint64)}
+			for i, t := range blockTypes {
+				if i < len(counts) {
+					blocks.Blocks[t] = counts[i]
+				}
+			}
+			info.Blocks = append(info.Blocks, blocks)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return PageTypeInfo{}, err
+	}
+
+	return info, nil
+}
@@ -0,0 +1,100 @@
+package numa
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// PCIDevice represent PCI device and its local NUMA node.
+type PCIDevice struct {
+	// Address is PCI address in domain:bus:device.function form, e.g. "0000:3b:00.0".
+	Address string
+	Class   uint32
+	Vendor  uint16
+	Device  uint16
+	// Node is -1 if firmware doesn't report device locality.
+	Node int
+	// LocalCPUs are CPUs local to the device.
+	LocalCPUs []int
+}
+
+// GetPCIDevices returns PCI devices with their local NUMA nodes.
+func GetPCIDevices(opts ...Option) ([]PCIDevice, error) {
+	o := newOptions(opts)
+	devicesPath := "bus/pci/devices"
+
+	dir, err := fs.ReadDir(o.sys, devicesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var devices []PCIDevice
+	for _, i := range dir {
+		d, err := parsePCIDevice(o.sys, path.Join(devicesPath, i.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", i.Name(), err)
+		}
+		devices = append(devices, d)
+	}
+
+	return devices, nil
+}
+
+func parsePCIDevice(fsys fs.FS, name string) (PCIDevice, error) {
+	d := PCIDevice{Address: path.Base(name), Node: -1}
+
+	class, err := readHex(fsys, path.Join(name, "class"))
+	if err != nil {
+		return PCIDevice{}, err
+	}
+	d.Class = uint32(class)
+
+	vendor, err := readHex(fsys, path.Join(name, "vendor"))
+	if err != nil {
+		return PCIDevice{}, err
+	}
+	d.Vendor = uint16(vendor)
+
+	device, err := readHex(fsys, path.Join(name, "device"))
+	if err != nil {
+		return PCIDevice{}, err
+	}
+	d.Device = uint16(device)
+
+	d.Node, err = readInt(fsys, path.Join(name, "numa_node"))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return PCIDevice{}, err
+		}
+		d.Node = -1
+	}
+
+	cpus, err := fs.ReadFile(fsys, path.Join(name, "local_cpulist"))
+	if err != nil && !os.IsNotExist(err) {
+		return PCIDevice{}, err
+	}
+	if d.LocalCPUs, err = parseList(string(cpus)); err != nil {
+		return PCIDevice{}, fmt.Errorf("parse local_cpulist: %w", err)
+	}
+
+	return d, nil
+}
+
+func readHex(fsys fs.FS, name string) (uint64, error) {
+	f, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return 0, err
+	}
+
+	// 0x060000\n
+	v, err := strconv.ParseUint(strings.TrimPrefix(strings.TrimSpace(string(f)), "0x"), 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("convert %q: %w", name, err)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,159 @@
+package numa
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// cpuset(2) levels and which values.
+const (
+	cpuLevelWhich  = 3
+	cpuWhichDomain = 6
+)
+
+const wordBits = int(unsafe.Sizeof(uintptr(0)) * 8)
+
+// platformNodes reads memory domains as nodes with sysctl and cpuset(2)
+// unless sysfs is given by WithSysRoot or WithSysFS.
+func platformNodes(o options) ([]Node, bool, error) {
+	if o.customSys {
+		return nil, false, nil
+	}
+
+	nodes, err := freebsdNodes()
+	return nodes, true, err
+}
+
+func freebsdNodes() ([]Node, error) {
+	count, err := syscall.SysctlUint32("vm.ndomains")
+	if err != nil {
+		return nil, fmt.Errorf("%w: vm.ndomains: %w", ErrNoNUMA, err)
+	}
+
+	// Locality isn't known without ACPI SLIT.
+	distances, err := physLocality(int(count))
+	if err != nil {
+		distances = nil
+	}
+
+	pageSize := uint64(os.Getpagesize())
+
+	nodes := make([]Node, 0, count)
+	for id := 0; id < int(count); id++ {
+		n := Node{ID: id, MemoryTier: -1, Online: true}
+
+		if n.CPU, err = domainCPUs(id); err != nil {
+			return nil, fmt.Errorf("domain %d cpus: %w", id, err)
+		}
+		n.HasCPU = len(n.CPU) > 0
+
+		stats := make(map[string]uint64)
+		for _, name := range []string{"page_count", "free_count", "inactive"} {
+			v, err := syscall.SysctlUint32(fmt.Sprintf("vm.domain.%d.stats.%s", id, name))
+			if err != nil {
+				return nil, fmt.Errorf("domain %d %s: %w", id, name, err)
+			}
+			stats[name] = uint64(v) * pageSize
+		}
+		n.MemTotal = stats["page_count"]
+		n.MemFree = stats["free_count"]
+		// Inactive pages are reclaimed before the domain runs out of memory.
+		n.MemAvailable = stats["free_count"] + stats["inactive"]
+		n.HasMemory = n.MemTotal > 0
+		n.HasNormalMemory = n.HasMemory
+
+		if id < len(distances) {
+			n.Distances = distances[id]
+		}
+
+		nodes = append(nodes, n)
+	}
+
+	return nodes, nil
+}
+
+// physLocality parses vm.phys_locality, rows of distances prefixed by domain:
+//
+//	0: 10 21
+//	1: 21 10
+func physLocality(count int) ([][]int, error) {
+	s, err := syscall.Sysctl("vm.phys_locality")
+	if err != nil {
+		return nil, err
+	}
+
+	distances := make([][]int, count)
+	for _, line := range strings.Split(s, "\n") {
+		domain, values, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+
+		id, err := strconv.Atoi(strings.TrimSpace(domain))
+		if err != nil || id < 0 || id >= count {
+			return nil, fmt.Errorf("parse locality %q", line)
+		}
+
+		for _, token := range strings.Fields(values) {
+			d, err := strconv.Atoi(token)
+			if err != nil {
+				return nil, fmt.Errorf("convert distance %q: %w", token, err)
+			}
+			// Distance is -1 if locality isn't known.
+			if d < 0 {
+				return nil, fmt.Errorf("unknown locality of domain %d", id)
+			}
+			distances[id] = append(distances[id], d)
+		}
+	}
+
+	return distances, nil
+}
+
+// domainCPUs returns CPUs of memory domain with cpuset_getaffinity(2).
+func domainCPUs(domain int) ([]int, error) {
+	// Kernel set could be larger than the guess, grow it until kernel accepts.
+	for words := 1024 / wordBits; ; words *= 2 {
+		mask := make([]uintptr, words)
+		_, errno := cpusetSyscall(syscall.SYS_CPUSET_GETAFFINITY, cpuLevelWhich, cpuWhichDomain, int64(domain),
+			uintptr(len(mask)*wordBits/8), uintptr(unsafe.Pointer(&mask[0])))
+		if errno == syscall.ERANGE && words < 1<<16 {
+			continue
+		}
+		if errno != 0 {
+			return nil, fmt.Errorf("cpuset_getaffinity: %w", errno)
+		}
+
+		var cpus []int
+		for word, w := range mask {
+			for bit := 0; bit < wordBits; bit++ {
+				if w&(1<<uint(bit)) != 0 {
+					cpus = append(cpus, word*wordBits+bit)
+				}
+			}
+		}
+		return cpus, nil
+	}
+}
+
+// cpusetSyscall calls cpuset(2) system call taking level, which, id_t id
+// and rest arguments. 64-bit id is passed in two words on 32-bit systems.
+func cpusetSyscall(trap, level, which uintptr, id int64, rest ...uintptr) (uintptr, syscall.Errno) {
+	args := []uintptr{level, which}
+	if wordBits == 64 {
+		args = append(args, uintptr(id))
+	} else {
+		args = append(args, uintptr(uint32(id)), uintptr(uint32(uint64(id)>>32)))
+	}
+	args = append(args, rest...)
+	for len(args) < 9 {
+		args = append(args, 0)
+	}
+
+	r, _, errno := syscall.Syscall9(trap, args[0], args[1], args[2], args[3], args[4], args[5], args[6], args[7], args[8])
+	return r, errno
+}
@@ -0,0 +1,135 @@
+//go:build illumos && cgo
+
+package numa
+
+/*
+#cgo LDFLAGS: -llgrp
+#include <sys/lgrp_user.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"sort"
+)
+
+// platformNodes reads leaf locality groups as nodes with liblgrp unless
+// sysfs is given by WithSysRoot or WithSysFS. Node IDs are lgroup IDs
+// and distances are CPU to memory latencies reported by lgrp_latency_cookie(3LGRP).
+func platformNodes(o options) ([]Node, bool, error) {
+	if o.customSys {
+		return nil, false, nil
+	}
+
+	nodes, err := lgroupNodes()
+	return nodes, true, err
+}
+
+func lgroupNodes() ([]Node, error) {
+	cookie, err := C.lgrp_init(C.LGRP_VIEW_OS)
+	if cookie == C.LGRP_COOKIE_NONE {
+		return nil, fmt.Errorf("lgrp_init: %w", err)
+	}
+	defer C.lgrp_fini(cookie)
+
+	leaves, err := leafLgroups(cookie, C.lgrp_root(cookie))
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(leaves, func(a, b int) bool {
+		return leaves[a] < leaves[b]
+	})
+
+	nodes := make([]Node, 0, len(leaves))
+	for _, id := range leaves {
+		n := Node{ID: int(id), MemoryTier: -1, Online: true}
+
+		if n.CPU, err = lgroupCPUs(cookie, id); err != nil {
+			return nil, err
+		}
+		n.HasCPU = len(n.CPU) > 0
+
+		total := C.lgrp_mem_size(cookie, id, C.LGRP_MEM_SZ_INSTALLED, C.LGRP_CONTENT_DIRECT)
+		free := C.lgrp_mem_size(cookie, id, C.LGRP_MEM_SZ_FREE, C.LGRP_CONTENT_DIRECT)
+		if total < 0 || free < 0 {
+			return nil, fmt.Errorf("lgrp_mem_size of lgroup %d failed", id)
+		}
+		n.MemTotal = uint64(total)
+		n.MemFree = uint64(free)
+		n.MemAvailable = uint64(free)
+		n.HasMemory = n.MemTotal > 0
+		n.HasNormalMemory = n.HasMemory
+
+		for _, to := range leaves {
+			latency := C.lgrp_latency_cookie(cookie, id, to, C.LGRP_LAT_CPU_TO_MEM)
+			if latency < 0 {
+				// Latencies aren't known, e.g. lgroup without CPUs.
+				n.Distances = nil
+				break
+			}
+			n.Distances = append(n.Distances, int(latency))
+			n.DistanceNodes = append(n.DistanceNodes, int(to))
+		}
+		if n.Distances == nil {
+			n.DistanceNodes = nil
+		}
+
+		nodes = append(nodes, n)
+	}
+
+	return nodes, nil
+}
+
+// leafLgroups returns lgroups without children under parent, parent itself
+// if it has no children, e.g. root lgroup of UMA machine.
+func leafLgroups(cookie C.lgrp_cookie_t, parent C.lgrp_id_t) ([]C.lgrp_id_t, error) {
+	count, err := C.lgrp_children(cookie, parent, nil, 0)
+	if count < 0 {
+		return nil, fmt.Errorf("lgrp_children %d: %w", parent, err)
+	}
+	if count == 0 {
+		return []C.lgrp_id_t{parent}, nil
+	}
+
+	children := make([]C.lgrp_id_t, count)
+	count, err = C.lgrp_children(cookie, parent, &children[0], C.uint(count))
+	if count < 0 {
+		return nil, fmt.Errorf("lgrp_children %d: %w", parent, err)
+	}
+
+	var leaves []C.lgrp_id_t
+	for _, child := range children[:count] {
+		l, err := leafLgroups(cookie, child)
+		if err != nil {
+			return nil, err
+		}
+		leaves = append(leaves, l...)
+	}
+
+	return leaves, nil
+}
+
+// lgroupCPUs returns CPUs directly contained in lgroup.
+func lgroupCPUs(cookie C.lgrp_cookie_t, id C.lgrp_id_t) ([]int, error) {
+	count, err := C.lgrp_cpus(cookie, id, nil, 0, C.LGRP_CONTENT_DIRECT)
+	if count < 0 {
+		return nil, fmt.Errorf("lgrp_cpus %d: %w", id, err)
+	}
+	if count == 0 {
+		return nil, nil
+	}
+
+	ids := make([]C.processorid_t, count)
+	count, err = C.lgrp_cpus(cookie, id, &ids[0], C.uint(count), C.LGRP_CONTENT_DIRECT)
+	if count < 0 {
+		return nil, fmt.Errorf("lgrp_cpus %d: %w", id, err)
+	}
+
+	cpus := make([]int, 0, count)
+	for _, cpu := range ids[:count] {
+		cpus = append(cpus, int(cpu))
+	}
+	sort.Ints(cpus)
+
+	return cpus, nil
+}
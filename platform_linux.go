@@ -0,0 +1,94 @@
+package numa
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// platformNodes synthesizes node 0 from /proc/cpuinfo and /proc/meminfo if
+// kernel has no node sysfs directory, e.g. it's built without CONFIG_NUMA,
+// ok is false if nodes should be read from sysfs. Sysfs given by WithSysRoot
+// or WithSysFS is always read as is.
+func platformNodes(o options) (nodes []Node, ok bool, err error) {
+	if o.customSys {
+		return nil, false, nil
+	}
+	if _, err := fs.Stat(o.sys, "devices/system/node"); !errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+
+	o.debug("node sysfs not found, node 0 synthesized from procfs")
+
+	n, err := procNode(o)
+	if err != nil {
+		return nil, true, fmt.Errorf("%w: %w", ErrNoNUMA, err)
+	}
+
+	return []Node{n}, true, nil
+}
+
+// procNode returns node with all CPUs and memory of the system.
+func procNode(o options) (Node, error) {
+	meminfo, err := parseMemInfo(o, o.proc, "meminfo")
+	if err != nil {
+		return Node{}, fmt.Errorf("parse meminfo: %w", err)
+	}
+
+	cpus, err := parseProcCPUs(o)
+	if err != nil {
+		return Node{}, fmt.Errorf("parse cpuinfo: %w", err)
+	}
+
+	n := syntheticNode(meminfo.MemTotal)
+	n.CPU = cpus
+	n.HasCPU = len(cpus) > 0
+	n.MemFree = meminfo.MemFree
+	n.MemInfo = meminfo
+
+	// MemAvailable is reported by kernels since 3.14.
+	if available, ok := meminfo.Raw["MemAvailable"]; ok {
+		n.MemAvailable = available
+	} else {
+		n.MemAvailable = calculateAvailableMemory(meminfo, nil, errNotRead)
+	}
+
+	return n, nil
+}
+
+// parseProcCPUs returns IDs of "processor" lines of /proc/cpuinfo.
+func parseProcCPUs(o options) ([]int, error) {
+	f, err := o.proc.Open("cpuinfo")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var cpus []int
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// processor	: 0
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok || strings.TrimSpace(key) != "processor" {
+			continue
+		}
+
+		cpu, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			if err := o.malformedValue("cpuinfo", fmt.Errorf("convert processor %q: %w", value, err)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		cpus = append(cpus, cpu)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return cpus, nil
+}
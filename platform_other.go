@@ -0,0 +1,18 @@
+//go:build !linux && !windows && !freebsd && !(illumos && cgo)
+
+package numa
+
+// platformNodes returns synthetic node if it's enabled by WithSyntheticNode,
+// otherwise nodes are read from sysfs given by options.
+func platformNodes(o options) ([]Node, bool, error) {
+	if o.customSys || !o.syntheticNode {
+		return nil, false, nil
+	}
+
+	total, err := systemMemory()
+	if err != nil {
+		return nil, true, err
+	}
+
+	return []Node{syntheticNode(total)}, true, nil
+}
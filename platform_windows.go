@@ -0,0 +1,280 @@
+package numa
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32 = syscall.NewLazyDLL("kernel32.dll")
+
+	procGetLogicalProcessorInformationEx = kernel32.NewProc("GetLogicalProcessorInformationEx")
+	procGetNumaAvailableMemoryNodeEx     = kernel32.NewProc("GetNumaAvailableMemoryNodeEx")
+	procGlobalMemoryStatusEx             = kernel32.NewProc("GlobalMemoryStatusEx")
+	procGetActiveProcessorGroupCount     = kernel32.NewProc("GetActiveProcessorGroupCount")
+	procGetActiveProcessorCount          = kernel32.NewProc("GetActiveProcessorCount")
+)
+
+const (
+	relationNumaNode = 1
+	// relationNumaNodeEx returns all groups of nodes, RelationNumaNode returns
+	// only the primary group since Windows 11 and Server 2022.
+	relationNumaNodeEx = 6
+
+	errorInvalidParameter   syscall.Errno = 87
+	errorInsufficientBuffer syscall.Errno = 122
+
+	// groupAffinitySize is size of GROUP_AFFINITY: KAFFINITY mask,
+	// WORD group and 3 reserved WORDs.
+	groupAffinitySize = int(unsafe.Sizeof(groupAffinity{}))
+
+	wordBits = int(unsafe.Sizeof(uintptr(0)) * 8)
+)
+
+// memoryStatusEx represent MEMORYSTATUSEX.
+type memoryStatusEx struct {
+	length               uint32
+	memoryLoad           uint32
+	totalPhys            uint64
+	availPhys            uint64
+	totalPageFile        uint64
+	availPageFile        uint64
+	totalVirtual         uint64
+	availVirtual         uint64
+	availExtendedVirtual uint64
+}
+
+// platformNodes reads nodes with Windows API unless sysfs is given
+// by WithSysRoot or WithSysFS. Windows doesn't expose node distances
+// and memory of node except available one, MemTotal is set only on
+// single node machines.
+func platformNodes(o options) ([]Node, bool, error) {
+	if o.customSys {
+		return nil, false, nil
+	}
+
+	nodes, err := windowsNodes()
+	return nodes, true, err
+}
+
+func windowsNodes() ([]Node, error) {
+	affinities, err := nodeAffinities()
+	if err != nil {
+		return nil, err
+	}
+
+	offsets, err := groupOffsets()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]Node, 0, len(affinities))
+	for id, groups := range affinities {
+		n := Node{ID: id, MemoryTier: -1, Online: true}
+
+		for _, g := range groups {
+			if int(g.Group) >= len(offsets) {
+				return nil, fmt.Errorf("node%d: processor group %d isn't active", id, g.Group)
+			}
+			for bit := 0; bit < wordBits; bit++ {
+				if g.Mask&(1<<uint(bit)) != 0 {
+					n.CPU = append(n.CPU, offsets[g.Group]+bit)
+				}
+			}
+		}
+		sort.Ints(n.CPU)
+		n.HasCPU = len(n.CPU) > 0
+
+		available, err := numaAvailableMemory(id)
+		if err != nil {
+			return nil, fmt.Errorf("node%d: %w", id, err)
+		}
+		n.MemFree = available
+		n.MemAvailable = available
+		n.HasMemory = available > 0
+		n.HasNormalMemory = n.HasMemory
+
+		nodes = append(nodes, n)
+	}
+
+	if len(nodes) == 0 {
+		return nil, ErrNoNUMA
+	}
+
+	sort.Slice(nodes, func(a, b int) bool {
+		return nodes[a].ID < nodes[b].ID
+	})
+
+	if len(nodes) == 1 {
+		total, err := totalPhysicalMemory()
+		if err != nil {
+			return nil, err
+		}
+		nodes[0].MemTotal = total
+	}
+
+	return nodes, nil
+}
+
+// groupAffinity represent GROUP_AFFINITY, processors of single processor group.
+type groupAffinity struct {
+	Mask     uintptr
+	Group    uint16
+	Reserved [3]uint16
+}
+
+// nodeAffinities returns processor groups of nodes keyed by node ID,
+// node spans several groups on machines with more than 64 processors.
+func nodeAffinities() (map[int][]groupAffinity, error) {
+	buf, err := logicalProcessorInformation(relationNumaNodeEx)
+	if errors.Is(err, errorInvalidParameter) {
+		// Windows before RelationNumaNodeEx returns all groups with RelationNumaNode.
+		buf, err = logicalProcessorInformation(relationNumaNode)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	affinities := make(map[int][]groupAffinity)
+	for len(buf) > 0 {
+		// SYSTEM_LOGICAL_PROCESSOR_INFORMATION_EX: DWORD relationship, DWORD size.
+		if len(buf) < 8 {
+			return nil, errors.New("truncated logical processor information")
+		}
+		relationship := binary.LittleEndian.Uint32(buf[0:4])
+		size := int(binary.LittleEndian.Uint32(buf[4:8]))
+		if size < 8 || size > len(buf) {
+			return nil, fmt.Errorf("logical processor information size %d is out of range", size)
+		}
+		record := buf[:size]
+		buf = buf[size:]
+
+		if relationship != relationNumaNode && relationship != relationNumaNodeEx {
+			continue
+		}
+
+		id, groups, err := parseNumaNodeRelationship(record)
+		if err != nil {
+			return nil, err
+		}
+		affinities[id] = append(affinities[id], groups...)
+	}
+
+	return affinities, nil
+}
+
+// parseNumaNodeRelationship parses SYSTEM_LOGICAL_PROCESSOR_INFORMATION_EX
+// record with NUMA_NODE_RELATIONSHIP: DWORD node number, 18 reserved bytes,
+// WORD group count and GROUP_AFFINITY masks.
+func parseNumaNodeRelationship(record []byte) (int, []groupAffinity, error) {
+	if len(record) < 32+groupAffinitySize {
+		return 0, nil, fmt.Errorf("numa node relationship size %d is too small", len(record))
+	}
+
+	id := int(binary.LittleEndian.Uint32(record[8:12]))
+
+	// Group count is 0 before Windows 11 and Server 2022, single mask is set then.
+	groupCount := int(binary.LittleEndian.Uint16(record[30:32]))
+	if groupCount == 0 {
+		groupCount = 1
+	}
+
+	groups := make([]groupAffinity, 0, groupCount)
+	for i := 0; i < groupCount; i++ {
+		offset := 32 + i*groupAffinitySize
+		if offset+groupAffinitySize > len(record) {
+			return 0, nil, fmt.Errorf("node%d: group %d is out of record", id, i)
+		}
+
+		var g groupAffinity
+		if groupAffinitySize == 16 {
+			g.Mask = uintptr(binary.LittleEndian.Uint64(record[offset:]))
+		} else {
+			g.Mask = uintptr(binary.LittleEndian.Uint32(record[offset:]))
+		}
+		g.Group = binary.LittleEndian.Uint16(record[offset+groupAffinitySize-8:])
+		groups = append(groups, g)
+	}
+
+	return id, groups, nil
+}
+
+// groupOffsets returns CPU ID of the first processor of each active processor
+// group. CPUs are numbered across groups in order, e.g. with two groups of 48
+// processors CPUs of the second group are 48-95.
+func groupOffsets() ([]int, error) {
+	if err := procGetActiveProcessorGroupCount.Find(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnsupportedKernel, err)
+	}
+
+	count, _, _ := procGetActiveProcessorGroupCount.Call()
+
+	offsets := make([]int, 0, count)
+	var offset int
+	for group := uintptr(0); group < count; group++ {
+		offsets = append(offsets, offset)
+
+		processors, _, err := procGetActiveProcessorCount.Call(group)
+		if processors == 0 {
+			return nil, fmt.Errorf("GetActiveProcessorCount %d: %w", group, err)
+		}
+		offset += int(processors)
+	}
+
+	return offsets, nil
+}
+
+// logicalProcessorInformation returns GetLogicalProcessorInformationEx buffer.
+func logicalProcessorInformation(relationship uint32) ([]byte, error) {
+	if err := procGetLogicalProcessorInformationEx.Find(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnsupportedKernel, err)
+	}
+
+	var length uint32
+	r, _, err := procGetLogicalProcessorInformationEx.Call(uintptr(relationship), 0, uintptr(unsafe.Pointer(&length)))
+	if r == 0 && !errors.Is(err, errorInsufficientBuffer) {
+		return nil, fmt.Errorf("GetLogicalProcessorInformationEx: %w", err)
+	}
+	if length == 0 {
+		return nil, ErrNoNUMA
+	}
+
+	buf := make([]byte, length)
+	r, _, err = procGetLogicalProcessorInformationEx.Call(uintptr(relationship),
+		uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&length)))
+	if r == 0 {
+		return nil, fmt.Errorf("GetLogicalProcessorInformationEx: %w", err)
+	}
+
+	return buf[:length], nil
+}
+
+// numaAvailableMemory returns available memory of node in bytes.
+func numaAvailableMemory(node int) (uint64, error) {
+	if err := procGetNumaAvailableMemoryNodeEx.Find(); err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrUnsupportedKernel, err)
+	}
+
+	var available uint64
+	r, _, err := procGetNumaAvailableMemoryNodeEx.Call(uintptr(uint16(node)), uintptr(unsafe.Pointer(&available)))
+	if r == 0 {
+		return 0, fmt.Errorf("GetNumaAvailableMemoryNodeEx: %w", err)
+	}
+
+	return available, nil
+}
+
+// totalPhysicalMemory returns physical memory of the system in bytes.
+func totalPhysicalMemory() (uint64, error) {
+	status := memoryStatusEx{length: uint32(unsafe.Sizeof(memoryStatusEx{}))}
+	r, _, err := procGlobalMemoryStatusEx.Call(uintptr(unsafe.Pointer(&status)))
+	if r == 0 {
+		return 0, fmt.Errorf("GlobalMemoryStatusEx: %w", err)
+	}
+
+	return status.totalPhys, nil
+}
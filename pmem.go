@@ -0,0 +1,96 @@
+package numa
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+// PMEMDevice represent persistent memory region, namespace or DAX device.
+type PMEMDevice struct {
+	// Name is sysfs device name, e.g. "region0", "namespace0.0" or "dax0.0".
+	Name string
+	// Kind is one of "region", "namespace" or "dax".
+	Kind string
+	Size uint64
+	// Node is NUMA node closest to the device, -1 if unknown.
+	Node int
+	// TargetNode is NUMA node the device memory is surfaced as
+	// when onlined as system RAM, -1 if unknown.
+	TargetNode int
+}
+
+// GetPMEMDevices returns persistent memory regions, namespaces and DAX devices
+// with their NUMA nodes.
+func GetPMEMDevices(opts ...Option) ([]PMEMDevice, error) {
+	o := newOptions(opts)
+
+	var devices []PMEMDevice
+
+	for _, source := range []struct {
+		path  string
+		kinds []string
+	}{
+		{"bus/nd/devices", []string{"region", "namespace"}},
+		{"class/dax", []string{"dax"}},
+	} {
+		dir, err := fs.ReadDir(o.sys, source.path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		for _, i := range dir {
+			var kind string
+			for _, k := range source.kinds {
+				if strings.HasPrefix(i.Name(), k) {
+					kind = k
+				}
+			}
+			if kind == "" {
+				continue
+			}
+
+			d, err := parsePMEMDevice(o.sys, path.Join(source.path, i.Name()), kind)
+			if err != nil {
+				return nil, err
+			}
+			devices = append(devices, d)
+		}
+	}
+
+	return devices, nil
+}
+
+func parsePMEMDevice(fsys fs.FS, name, kind string) (PMEMDevice, error) {
+	d := PMEMDevice{Name: path.Base(name), Kind: kind}
+
+	var err error
+	if d.Size, err = readUint64(fsys, path.Join(name, "size")); err != nil && !os.IsNotExist(err) {
+		return PMEMDevice{}, err
+	}
+	if d.Node, err = readOptionalNode(fsys, path.Join(name, "numa_node")); err != nil {
+		return PMEMDevice{}, err
+	}
+	if d.TargetNode, err = readOptionalNode(fsys, path.Join(name, "target_node")); err != nil {
+		return PMEMDevice{}, err
+	}
+
+	return d, nil
+}
+
+// readOptionalNode returns node ID from file or -1 if the file doesn't exist.
+func readOptionalNode(fsys fs.FS, name string) (int, error) {
+	node, err := readInt(fsys, name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return -1, nil
+		}
+		return -1, err
+	}
+
+	return node, nil
+}
@@ -0,0 +1,41 @@
+package policy
+
+import (
+	"fmt"
+	"syscall"
+
+	"github.com/oneumyvakin/numa"
+)
+
+// AllocOnNode returns size bytes of anonymous memory bound to node, pages are
+// allocated on node when touched. Memory isn't managed by Go garbage collector
+// and must be released with Free.
+func AllocOnNode(size, node int) ([]byte, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("allocation size %d is not positive", size)
+	}
+
+	b, err := syscall.Mmap(-1, 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_PRIVATE|syscall.MAP_ANONYMOUS)
+	if err != nil {
+		return nil, fmt.Errorf("mmap %d bytes: %w", size, err)
+	}
+
+	if err := MbindBytes(b, Bind, numa.NewNodeSet(node), 0); err != nil {
+		syscall.Munmap(b)
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// Free releases memory returned by AllocOnNode.
+func Free(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+
+	if err := syscall.Munmap(b); err != nil {
+		return fmt.Errorf("munmap: %w", err)
+	}
+	return nil
+}
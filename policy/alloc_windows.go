@@ -0,0 +1,61 @@
+package policy
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32 = syscall.NewLazyDLL("kernel32.dll")
+
+	procVirtualAllocExNuma = kernel32.NewProc("VirtualAllocExNuma")
+	procVirtualFree        = kernel32.NewProc("VirtualFree")
+)
+
+const (
+	memCommit     = 0x1000
+	memReserve    = 0x2000
+	memRelease    = 0x8000
+	pageReadWrite = 0x04
+)
+
+// AllocOnNode returns size bytes of memory of the current process preferring
+// node, Windows allocates pages on other nodes if node has no free memory.
+// Memory isn't managed by Go garbage collector and must be released with Free.
+func AllocOnNode(size, node int) ([]byte, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("allocation size %d is not positive", size)
+	}
+	if err := procVirtualAllocExNuma.Find(); err != nil {
+		return nil, err
+	}
+
+	process, err := syscall.GetCurrentProcess()
+	if err != nil {
+		return nil, err
+	}
+
+	addr, _, err := procVirtualAllocExNuma.Call(uintptr(process), 0, uintptr(size),
+		memReserve|memCommit, pageReadWrite, uintptr(node))
+	if addr == 0 {
+		return nil, fmt.Errorf("VirtualAllocExNuma %d bytes on node %d: %w", size, node, err)
+	}
+
+	// Memory is outside of Go heap, pointer is converted without arithmetics.
+	p := *(*unsafe.Pointer)(unsafe.Pointer(&addr))
+	return unsafe.Slice((*byte)(p), size), nil
+}
+
+// Free releases memory returned by AllocOnNode.
+func Free(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+
+	r, _, err := procVirtualFree.Call(uintptr(unsafe.Pointer(&b[0])), 0, memRelease)
+	if r == 0 {
+		return fmt.Errorf("VirtualFree: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/oneumyvakin/numa"
+)
+
+// BindProcess binds already running process to CPUs of NUMA node and moves
+// its existing pages residing on other nodes to the node. Every thread is
+// restricted to the node CPUs. Unlike `numactl --membind`, future allocations
+// aren't bound: kernel doesn't allow to change memory policy of another process,
+// so they follow its own policy, which for default policy means the node local
+// to the running CPU, i.e. this node, and other nodes when it's full.
+// It returns number of pages which could not be moved.
+func BindProcess(pid, node int) (int, error) {
+	// Only CPUs of the node and IDs of other nodes are needed,
+	// so nodes whose other files can't be read don't fail binding.
+	n, err := numa.GetNode(node, numa.WithFields(0))
+	if err != nil {
+		return 0, err
+	}
+	if len(n.CPU) == 0 {
+		return 0, fmt.Errorf("node %d has no CPUs", node)
+	}
+
+	ids, err := numa.NodeIDs()
+	if err != nil {
+		return 0, err
+	}
+
+	var others numa.NodeSet
+	for _, id := range ids {
+		if id != node {
+			others.Add(id)
+		}
+	}
+
+	if err := setProcessAffinity(pid, n.CPU); err != nil {
+		return 0, err
+	}
+
+	if others.Len() == 0 {
+		return 0, nil
+	}
+
+	return MigratePages(pid, others, numa.NewNodeSet(node))
+}
+
+// setProcessAffinity sets affinity of every thread of process, including threads
+// started while walking the thread list. Pid 0 means the calling process.
+func setProcessAffinity(pid int, cpus []int) error {
+	if pid == 0 {
+		pid = os.Getpid()
+	}
+
+	done := make(map[int]bool)
+	for {
+		dir, err := os.ReadDir(filepath.Join("/proc", strconv.Itoa(pid), "task"))
+		if err != nil {
+			return err
+		}
+
+		var changed bool
+		for _, i := range dir {
+			tid, err := strconv.Atoi(i.Name())
+			if err != nil || done[tid] {
+				continue
+			}
+
+			if err := numa.SetThreadAffinity(tid, cpus); err != nil {
+				// Thread exited meanwhile.
+				if _, statErr := os.Stat(filepath.Join("/proc", strconv.Itoa(pid), "task", i.Name())); os.IsNotExist(statErr) {
+					continue
+				}
+				return err
+			}
+
+			done[tid] = true
+			changed = true
+		}
+
+		if !changed {
+			return nil
+		}
+	}
+}
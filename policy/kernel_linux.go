@@ -0,0 +1,83 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/oneumyvakin/numa"
+)
+
+var (
+	kernelOnce  sync.Once
+	kernelMajor int
+	kernelMinor int
+)
+
+// kernelAtLeast reports whether running kernel version is at least major.minor.
+func kernelAtLeast(major, minor int) bool {
+	kernelOnce.Do(func() {
+		var uts syscall.Utsname
+		if err := syscall.Uname(&uts); err != nil {
+			return
+		}
+
+		var release strings.Builder
+		for _, c := range uts.Release {
+			if c == 0 {
+				break
+			}
+			release.WriteByte(byte(c))
+		}
+
+		// 5.15.0-91-generic
+		parts := strings.SplitN(release.String(), ".", 3)
+		if len(parts) < 2 {
+			return
+		}
+		kernelMajor, _ = strconv.Atoi(parts[0])
+		kernelMinor, _ = strconv.Atoi(strings.TrimRightFunc(parts[1], func(r rune) bool {
+			return r < '0' || r > '9'
+		}))
+	})
+
+	return kernelMajor > major || kernelMajor == major && kernelMinor >= minor
+}
+
+// supportedMode replaces mode unsupported by running kernel with the closest supported one.
+func supportedMode(mode Mode, nodes numa.NodeSet) (Mode, numa.NodeSet) {
+	if mode.Base() == PreferredMany && !kernelAtLeast(5, 15) {
+		var preferred numa.NodeSet
+		if ids := nodes.IDs(); len(ids) > 0 {
+			preferred.Add(ids[0])
+		}
+		return Preferred | mode&modeFlags, preferred
+	}
+
+	if mode.Base() == WeightedInterleave && !kernelAtLeast(6, 9) {
+		return Interleave | mode&modeFlags, nodes
+	}
+
+	return mode, nodes
+}
+
+// errnoErr wraps ENOSYS with numa.ErrUnsupportedKernel.
+func errnoErr(errno syscall.Errno) error {
+	if errno == syscall.ENOSYS {
+		return fmt.Errorf("%w: %w", numa.ErrUnsupportedKernel, errno)
+	}
+	return errno
+}
+
+// unsupportedErr wraps error of missing sysfs file of a newer kernel
+// with numa.ErrUnsupportedKernel.
+func unsupportedErr(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %w", numa.ErrUnsupportedKernel, err)
+	}
+	return err
+}
@@ -0,0 +1,68 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+
+	"github.com/oneumyvakin/numa"
+)
+
+// MbindFlags control mbind behavior for already allocated pages.
+type MbindFlags int
+
+// mbind(2) flags.
+const (
+	// MbindStrict fails with EIO if existing pages don't follow the policy.
+	MbindStrict MbindFlags = 1 << 0
+	// MbindMove moves pages used only by this process to follow the policy.
+	MbindMove MbindFlags = 1 << 1
+	// MbindMoveAll moves all pages of the range, including shared ones. It requires CAP_SYS_NICE.
+	MbindMoveAll MbindFlags = 1 << 2
+)
+
+// Mbind sets memory policy of page aligned memory range, e.g. obtained with mmap.
+func Mbind(addr, length uintptr, mode Mode, nodes numa.NodeSet, flags MbindFlags) error {
+	if addr%uintptr(os.Getpagesize()) != 0 {
+		return fmt.Errorf("mbind: address %#x is not page aligned", addr)
+	}
+
+	mode, nodes = supportedMode(mode, nodes)
+	mask, maxNode := nodemask(nodes)
+
+	var maskPtr unsafe.Pointer
+	if len(mask) > 0 {
+		maskPtr = unsafe.Pointer(&mask[0])
+	}
+
+	_, _, errno := syscall.Syscall6(syscall.SYS_MBIND,
+		addr, length, uintptr(mode), uintptr(maskPtr), maxNode, uintptr(flags))
+	if errno != 0 {
+		return fmt.Errorf("mbind %#x+%d %s %s: %w", addr, length, mode, nodes, errnoErr(errno))
+	}
+
+	return nil
+}
+
+// nodemask returns nodemask words of nodes and maxnode argument of mbind.
+// Kernel reads maxnode-1 bits of the mask, so maxnode is one more than its bits.
+func nodemask(nodes numa.NodeSet) ([]uintptr, uintptr) {
+	mask := nodes.Uintptrs()
+	return mask, uintptr(len(mask)*wordBits + 1)
+}
+
+// MbindPointer sets memory policy of page aligned memory range starting at p.
+func MbindPointer(p unsafe.Pointer, length uintptr, mode Mode, nodes numa.NodeSet, flags MbindFlags) error {
+	return Mbind(uintptr(p), length, mode, nodes, flags)
+}
+
+// MbindBytes sets memory policy of memory backing b. The slice must start
+// on page boundary, i.e. be allocated with syscall.Mmap, because Go heap
+// memory is shared with unrelated objects.
+func MbindBytes(b []byte, mode Mode, nodes numa.NodeSet, flags MbindFlags) error {
+	if len(b) == 0 {
+		return nil
+	}
+	return Mbind(uintptr(unsafe.Pointer(&b[0])), uintptr(len(b)), mode, nodes, flags)
+}
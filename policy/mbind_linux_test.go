@@ -0,0 +1,127 @@
+package policy
+
+import (
+	"errors"
+	"slices"
+	"syscall"
+	"testing"
+	"unsafe"
+
+	"github.com/oneumyvakin/numa"
+)
+
+func TestNodemask(t *testing.T) {
+	tests := []struct {
+		nodes   numa.NodeSet
+		mask    []uintptr
+		maxNode uintptr
+	}{
+		{numa.NodeSet{}, nil, 1},
+		{numa.NewNodeSet(0), []uintptr{1}, uintptr(wordBits + 1)},
+		{numa.NewNodeSet(1, 3), []uintptr{0b1010}, uintptr(wordBits + 1)},
+		{numa.NewNodeSet(0, wordBits), []uintptr{1, 1}, uintptr(2*wordBits + 1)},
+	}
+	for _, tt := range tests {
+		mask, maxNode := nodemask(tt.nodes)
+		if !slices.Equal(mask, tt.mask) || maxNode != tt.maxNode {
+			t.Errorf("nodemask(%s) = %v, %d, want %v, %d", tt.nodes, mask, maxNode, tt.mask, tt.maxNode)
+		}
+	}
+}
+
+func TestMbindFlags(t *testing.T) {
+	// MPOL_MF_STRICT, MPOL_MF_MOVE and MPOL_MF_MOVE_ALL of linux/mempolicy.h.
+	for _, tt := range []struct {
+		flag MbindFlags
+		want int
+	}{
+		{MbindStrict, 1},
+		{MbindMove, 2},
+		{MbindMoveAll, 4},
+	} {
+		if int(tt.flag) != tt.want {
+			t.Errorf("flag = %d, want %d", tt.flag, tt.want)
+		}
+	}
+}
+
+func TestMbindUnaligned(t *testing.T) {
+	if err := Mbind(1, 1, Bind, numa.NewNodeSet(0), 0); err == nil {
+		t.Error("Mbind of unaligned address returned no error")
+	}
+}
+
+// mpolFAddr makes get_mempolicy return policy of the address.
+const mpolFAddr = 1 << 1
+
+func addrPolicy(t *testing.T, b []byte) (Mode, numa.NodeSet) {
+	t.Helper()
+
+	var mode int32
+	mask := make([]uintptr, maxNodes/wordBits)
+	if err := getMemPolicy(&mode, mask, uintptr(unsafe.Pointer(&b[0])), mpolFAddr); err != nil {
+		t.Fatal(err)
+	}
+
+	return Mode(mode), numa.NodeSetFromUintptrs(mask)
+}
+
+func mmapPage(t *testing.T) []byte {
+	t.Helper()
+
+	b, err := syscall.Mmap(-1, 0, syscall.Getpagesize(), syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_ANON|syscall.MAP_PRIVATE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { syscall.Munmap(b) })
+
+	return b
+}
+
+func TestMbindNode0(t *testing.T) {
+	nodes, err := numa.NodesWithMemory()
+	if err != nil || !nodes.Contains(0) {
+		t.Skip("requires node 0 with memory")
+	}
+
+	b := mmapPage(t)
+	err = MbindBytes(b, Bind, numa.NewNodeSet(0), 0)
+	if errors.Is(err, syscall.ENOSYS) || errors.Is(err, syscall.EPERM) {
+		t.Skipf("mbind isn't available: %v", err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mode, policyNodes := addrPolicy(t, b); mode.Base() != Bind || !policyNodes.Equal(numa.NewNodeSet(0)) {
+		t.Errorf("policy = %s %s, want %s 0", mode, policyNodes, Bind)
+	}
+}
+
+func TestMbindMultiNode(t *testing.T) {
+	nodes, err := numa.NodesWithMemory()
+	if err != nil || nodes.Len() < 2 {
+		t.Skip("requires at least two nodes with memory")
+	}
+	ids := nodes.IDs()
+	target := ids[len(ids)-1]
+
+	b := mmapPage(t)
+	if err := MbindBytes(b, Bind, numa.NewNodeSet(target), MbindStrict); err != nil {
+		t.Fatal(err)
+	}
+	b[0] = 1
+
+	mode, policyNodes := addrPolicy(t, b)
+	if mode.Base() != Bind || !policyNodes.Equal(numa.NewNodeSet(target)) {
+		t.Errorf("policy = %s %s, want %s %d", mode, policyNodes, Bind, target)
+	}
+
+	node, err := PageNode(uintptr(unsafe.Pointer(&b[0])))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node != target {
+		t.Errorf("page is on node%d, want node%d", node, target)
+	}
+}
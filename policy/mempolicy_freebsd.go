@@ -0,0 +1,133 @@
+package policy
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+
+	"github.com/oneumyvakin/numa"
+)
+
+const wordBits = int(unsafe.Sizeof(uintptr(0)) * 8)
+
+// cpuset(2) levels and which values.
+const (
+	cpuLevelWhich = 3
+	cpuWhichTid   = 1
+)
+
+// domainset(9) policies.
+const (
+	domainsetPolicyRoundRobin = 1
+	domainsetPolicyFirstTouch = 2
+	domainsetPolicyPrefer     = 3
+)
+
+// SetMemPolicy sets domainset of the calling thread with cpuset_setdomain(2).
+// Bind restricts allocations to nodes with first-touch policy, Preferred
+// prefers the single node of nodes, Interleave and WeightedInterleave
+// allocate from nodes in round-robin. Default and Local use first-touch
+// policy on all nodes. Mode flags are ignored.
+// Callers usually need runtime.LockOSThread because policy is per thread.
+func SetMemPolicy(mode Mode, nodes numa.NodeSet) error {
+	var policy int
+	switch mode.Base() {
+	case Default, Local:
+		ids, err := numa.NodeIDs()
+		if err != nil {
+			return err
+		}
+		nodes = numa.NewNodeSet(ids...)
+		policy = domainsetPolicyFirstTouch
+	case Bind:
+		policy = domainsetPolicyFirstTouch
+	case Preferred, PreferredMany:
+		// Domainset prefers exactly one domain.
+		var preferred numa.NodeSet
+		if ids := nodes.IDs(); len(ids) > 0 {
+			preferred.Add(ids[0])
+		}
+		nodes = preferred
+		policy = domainsetPolicyPrefer
+	case Interleave, WeightedInterleave:
+		policy = domainsetPolicyRoundRobin
+	default:
+		return fmt.Errorf("set domain policy: unsupported mode %s", mode)
+	}
+
+	mask := nodes.Uintptrs()
+	if len(mask) == 0 {
+		return fmt.Errorf("set domain policy %s: empty node set", mode)
+	}
+
+	// Kernel domainset could be larger than the mask, grow it until kernel accepts.
+	for len(mask) < 1<<10 {
+		_, errno := cpusetSyscall(sysCpusetSetdomain, cpuLevelWhich, cpuWhichTid, -1,
+			uintptr(len(mask)*wordBits/8), uintptr(unsafe.Pointer(&mask[0])), uintptr(policy))
+		if errno == syscall.ERANGE {
+			mask = append(mask, make([]uintptr, len(mask))...)
+			continue
+		}
+		if errno != 0 {
+			return fmt.Errorf("cpuset_setdomain %s %s: %w", mode, nodes, errnoErr(errno))
+		}
+		return nil
+	}
+
+	return fmt.Errorf("cpuset_setdomain %s %s: %w", mode, nodes, syscall.ERANGE)
+}
+
+// GetMemPolicy returns domainset of the calling thread as memory policy:
+// first-touch policy is Bind, prefer is Preferred and round-robin is Interleave.
+func GetMemPolicy() (Mode, numa.NodeSet, error) {
+	for words := 1; words <= 1<<10; words *= 2 {
+		mask := make([]uintptr, words)
+		var policy int32
+		_, errno := cpusetSyscall(sysCpusetGetdomain, cpuLevelWhich, cpuWhichTid, -1,
+			uintptr(len(mask)*wordBits/8), uintptr(unsafe.Pointer(&mask[0])), uintptr(unsafe.Pointer(&policy)))
+		if errno == syscall.ERANGE {
+			continue
+		}
+		if errno != 0 {
+			return 0, numa.NodeSet{}, fmt.Errorf("cpuset_getdomain: %w", errnoErr(errno))
+		}
+
+		nodes := numa.NodeSetFromUintptrs(mask)
+		switch policy {
+		case domainsetPolicyFirstTouch:
+			return Bind, nodes, nil
+		case domainsetPolicyPrefer:
+			return Preferred, nodes, nil
+		default:
+			return Interleave, nodes, nil
+		}
+	}
+
+	return 0, numa.NodeSet{}, fmt.Errorf("cpuset_getdomain: %w", syscall.ERANGE)
+}
+
+// cpusetSyscall calls cpuset(2) system call taking level, which, id_t id
+// and rest arguments. 64-bit id is passed in two words on 32-bit systems.
+func cpusetSyscall(trap, level, which uintptr, id int64, rest ...uintptr) (uintptr, syscall.Errno) {
+	args := []uintptr{level, which}
+	if wordBits == 64 {
+		args = append(args, uintptr(id))
+	} else {
+		args = append(args, uintptr(uint32(id)), uintptr(uint32(uint64(id)>>32)))
+	}
+	args = append(args, rest...)
+	for len(args) < 9 {
+		args = append(args, 0)
+	}
+
+	r, _, errno := syscall.Syscall9(trap, args[0], args[1], args[2], args[3], args[4], args[5], args[6], args[7], args[8])
+	return r, errno
+}
+
+// errnoErr wraps ENOSYS with numa.ErrUnsupportedKernel.
+func errnoErr(errno syscall.Errno) error {
+	if errno == syscall.ENOSYS {
+		return fmt.Errorf("%w: %w", numa.ErrUnsupportedKernel, errno)
+	}
+	return errno
+}
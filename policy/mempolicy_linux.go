@@ -0,0 +1,64 @@
+package policy
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+
+	"github.com/oneumyvakin/numa"
+)
+
+// maxNodes is the largest nodemask kernel could be configured with (NODES_SHIFT 10).
+const maxNodes = 1024
+
+const wordBits = int(unsafe.Sizeof(uintptr(0)) * 8)
+
+// mpolFMemsAllowed makes get_mempolicy return nodes allowed by cpuset.
+const mpolFMemsAllowed = 1 << 2
+
+// SetMemPolicy sets memory policy of the calling thread.
+// Callers usually need runtime.LockOSThread because policy is per thread.
+func SetMemPolicy(mode Mode, nodes numa.NodeSet) error {
+	mode, nodes = supportedMode(mode, nodes)
+	mask := nodes.Uintptrs()
+
+	var maskPtr unsafe.Pointer
+	if len(mask) > 0 {
+		maskPtr = unsafe.Pointer(&mask[0])
+	}
+
+	_, _, errno := syscall.Syscall(syscall.SYS_SET_MEMPOLICY,
+		uintptr(mode), uintptr(maskPtr), uintptr(len(mask)*wordBits+1))
+	if errno != 0 {
+		return fmt.Errorf("set_mempolicy %s %s: %w", mode, nodes, errnoErr(errno))
+	}
+
+	return nil
+}
+
+// GetMemPolicy returns memory policy of the calling thread and its nodes.
+func GetMemPolicy() (Mode, numa.NodeSet, error) {
+	var mode int32
+	mask := make([]uintptr, maxNodes/wordBits)
+
+	if err := getMemPolicy(&mode, mask, 0, 0); err != nil {
+		return 0, numa.NodeSet{}, fmt.Errorf("get_mempolicy: %w", err)
+	}
+
+	return Mode(mode), numa.NodeSetFromUintptrs(mask), nil
+}
+
+func getMemPolicy(mode *int32, mask []uintptr, addr uintptr, flags uintptr) error {
+	var maskPtr unsafe.Pointer
+	if len(mask) > 0 {
+		maskPtr = unsafe.Pointer(&mask[0])
+	}
+
+	_, _, errno := syscall.Syscall6(syscall.SYS_GET_MEMPOLICY,
+		uintptr(unsafe.Pointer(mode)), uintptr(maskPtr), uintptr(len(mask)*wordBits+1), addr, flags, 0)
+	if errno != 0 {
+		return errnoErr(errno)
+	}
+
+	return nil
+}
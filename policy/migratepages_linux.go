@@ -0,0 +1,36 @@
+package policy
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+
+	"github.com/oneumyvakin/numa"
+)
+
+// MigratePages moves all pages of process pid residing on fromNodes to toNodes.
+// Pid 0 means the calling process. It returns number of pages which could not be moved.
+func MigratePages(pid int, fromNodes, toNodes numa.NodeSet) (int, error) {
+	from := fromNodes.Uintptrs()
+	to := toNodes.Uintptrs()
+
+	// Kernel reads both masks with the same length.
+	for len(from) < len(to) {
+		from = append(from, 0)
+	}
+	for len(to) < len(from) {
+		to = append(to, 0)
+	}
+	if len(from) == 0 {
+		return 0, nil
+	}
+
+	r, _, errno := syscall.Syscall6(sysMigratePages,
+		uintptr(pid), uintptr(len(from)*wordBits+1),
+		uintptr(unsafe.Pointer(&from[0])), uintptr(unsafe.Pointer(&to[0])), 0, 0)
+	if errno != 0 {
+		return 0, fmt.Errorf("migrate_pages pid %d %s to %s: %w", pid, fromNodes, toNodes, errnoErr(errno))
+	}
+
+	return int(r), nil
+}
@@ -0,0 +1,60 @@
+package policy
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// MovePages moves pages of process pid to target nodes, nodes[i] is target
+// of pages[i]. Pid 0 means the calling process. Flags could be MbindMove or
+// MbindMoveAll, MbindMove is used if flags are 0.
+//
+// It returns status per page: node the page resides on after the call or
+// negative errno, e.g. -ENOENT for not yet faulted page.
+func MovePages(pid int, pages []uintptr, nodes []int, flags MbindFlags) ([]int, error) {
+	if len(nodes) != len(pages) {
+		return nil, fmt.Errorf("move_pages: %d pages but %d nodes", len(pages), len(nodes))
+	}
+	if flags == 0 {
+		flags = MbindMove
+	}
+	return movePages(pid, pages, nodes, flags)
+}
+
+// QueryPages returns node of each page of process pid without moving them.
+// Pid 0 means the calling process. Status of page is negative errno if its
+// node can't be determined, e.g. -ENOENT for not yet faulted page.
+func QueryPages(pid int, pages []uintptr) ([]int, error) {
+	return movePages(pid, pages, nil, 0)
+}
+
+func movePages(pid int, pages []uintptr, nodes []int, flags MbindFlags) ([]int, error) {
+	if len(pages) == 0 {
+		return nil, nil
+	}
+
+	var nodesPtr unsafe.Pointer
+	if nodes != nil {
+		n := make([]int32, len(nodes))
+		for i, node := range nodes {
+			n[i] = int32(node)
+		}
+		nodesPtr = unsafe.Pointer(&n[0])
+	}
+
+	status := make([]int32, len(pages))
+	_, _, errno := syscall.Syscall6(syscall.SYS_MOVE_PAGES,
+		uintptr(pid), uintptr(len(pages)), uintptr(unsafe.Pointer(&pages[0])),
+		uintptr(nodesPtr), uintptr(unsafe.Pointer(&status[0])), uintptr(flags))
+	if errno != 0 {
+		return nil, fmt.Errorf("move_pages pid %d: %w", pid, errnoErr(errno))
+	}
+
+	result := make([]int, len(status))
+	for i, s := range status {
+		result[i] = int(s)
+	}
+
+	return result, nil
+}
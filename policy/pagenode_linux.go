@@ -0,0 +1,36 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// PageNode returns node of the page containing address of the calling process.
+// It returns syscall.ENOENT error if the page is not faulted in yet.
+func PageNode(addr uintptr) (int, error) {
+	nodes, err := PagesNodes([]uintptr{addr})
+	if err != nil {
+		return -1, err
+	}
+
+	if nodes[0] < 0 {
+		return -1, fmt.Errorf("page %#x: %w", addr, syscall.Errno(-nodes[0]))
+	}
+
+	return nodes[0], nil
+}
+
+// PagesNodes returns node of the page containing each address of the calling process.
+// Node of the page is negative errno if it can't be determined, e.g. -ENOENT
+// for not yet faulted page.
+func PagesNodes(addrs []uintptr) ([]int, error) {
+	pageMask := ^uintptr(os.Getpagesize() - 1)
+
+	pages := make([]uintptr, len(addrs))
+	for i, addr := range addrs {
+		pages[i] = addr & pageMask
+	}
+
+	return QueryPages(0, pages)
+}
@@ -0,0 +1,188 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/oneumyvakin/numa"
+)
+
+// Policy represent intended NUMA placement of process.
+type Policy struct {
+	// CPUNodes are nodes whose CPUs threads are restricted to, empty set means any.
+	CPUNodes numa.NodeSet
+	// MemNodes are nodes memory should reside on, empty set means any.
+	MemNodes numa.NodeSet
+	// Mode is memory policy set with MemNodes. Kernel allows to set it only
+	// for the calling thread, so it's applied and verified only for pid 0.
+	Mode Mode
+	// Strict makes pages which could not be migrated an Apply error and
+	// any memory outside of MemNodes a violation. Otherwise such memory,
+	// e.g. shared libraries page cache, is only reported.
+	Strict bool
+}
+
+// Violation represent mismatch between Policy and live process state.
+type Violation struct {
+	// TID is thread ID for affinity violations, 0 otherwise.
+	TID     int
+	Message string
+}
+
+func (v Violation) String() string {
+	if v.TID != 0 {
+		return fmt.Sprintf("thread %d: %s", v.TID, v.Message)
+	}
+	return v.Message
+}
+
+// Report represent result of Policy verification.
+type Report struct {
+	Violations []Violation
+	// LocalBytes and RemoteBytes are process memory on and outside of MemNodes.
+	LocalBytes  uint64
+	RemoteBytes uint64
+}
+
+// OK reports whether there are no violations.
+func (r Report) OK() bool {
+	return len(r.Violations) == 0
+}
+
+// Apply restricts threads of process pid to CPUs of CPUNodes and migrates its
+// memory to MemNodes. For pid 0 memory policy of the calling thread is set too.
+func (p Policy) Apply(pid int) error {
+	if p.CPUNodes.Len() > 0 {
+		cpus, err := nodesCPUs(p.CPUNodes)
+		if err != nil {
+			return err
+		}
+		if cpus.Len() == 0 {
+			return fmt.Errorf("nodes %s have no CPUs", p.CPUNodes)
+		}
+		if err := setProcessAffinity(pid, cpus.IDs()); err != nil {
+			return err
+		}
+	}
+
+	if p.MemNodes.Len() == 0 {
+		return nil
+	}
+
+	ids, err := numa.NodeIDs()
+	if err != nil {
+		return err
+	}
+
+	var others numa.NodeSet
+	for _, id := range ids {
+		if !p.MemNodes.Contains(id) {
+			others.Add(id)
+		}
+	}
+
+	if others.Len() > 0 {
+		notMoved, err := MigratePages(pid, others, p.MemNodes)
+		if err != nil {
+			return err
+		}
+		if p.Strict && notMoved > 0 {
+			return fmt.Errorf("%d pages could not be migrated to nodes %s", notMoved, p.MemNodes)
+		}
+	}
+
+	if pid == 0 && p.Mode != Default {
+		return SetMemPolicy(p.Mode, p.MemNodes)
+	}
+
+	return nil
+}
+
+// Verify checks live state of process pid against the policy.
+func (p Policy) Verify(pid int) (Report, error) {
+	var r Report
+
+	if p.CPUNodes.Len() > 0 {
+		cpus, err := nodesCPUs(p.CPUNodes)
+		if err != nil {
+			return Report{}, err
+		}
+
+		threads, err := numa.GetThreads(pid)
+		if err != nil {
+			return Report{}, err
+		}
+
+		for _, t := range threads {
+			for _, cpu := range t.Affinity.IDs() {
+				if !cpus.Contains(cpu) {
+					r.Violations = append(r.Violations, Violation{
+						TID:     t.TID,
+						Message: fmt.Sprintf("affinity %s exceeds CPUs %s of nodes %s", t.Affinity, cpus, p.CPUNodes),
+					})
+					break
+				}
+			}
+		}
+	}
+
+	if p.MemNodes.Len() > 0 {
+		usage, err := numa.ProcessNodeUsage(pid)
+		if err != nil {
+			return Report{}, err
+		}
+
+		for node, bytes := range usage {
+			if p.MemNodes.Contains(node) {
+				r.LocalBytes += bytes
+			} else {
+				r.RemoteBytes += bytes
+			}
+		}
+
+		if p.Strict && r.RemoteBytes > 0 {
+			r.Violations = append(r.Violations, Violation{
+				Message: fmt.Sprintf("%d bytes reside outside of nodes %s", r.RemoteBytes, p.MemNodes),
+			})
+		}
+	}
+
+	if pid == 0 && p.Mode != Default {
+		mode, nodes, err := GetMemPolicy()
+		if err != nil {
+			return Report{}, err
+		}
+
+		want, wantNodes := supportedMode(p.Mode, p.MemNodes)
+		if mode != want || nodes.String() != wantNodes.String() {
+			r.Violations = append(r.Violations, Violation{
+				Message: fmt.Sprintf("memory policy %s %s, want %s %s", mode, nodes, want, wantNodes),
+			})
+		}
+	}
+
+	return r, nil
+}
+
+// nodesCPUs returns CPUs of nodes in set reading only node cpulists,
+// so nodes outside of set which can't be read are ignored.
+func nodesCPUs(set numa.NodeSet) (numa.CPUSet, error) {
+	var cpus numa.CPUSet
+	for n, err := range numa.Nodes(numa.WithFields(0)) {
+		var nodeErr *numa.NodeError
+		if errors.As(err, &nodeErr) && !set.Contains(nodeErr.Node) {
+			continue
+		}
+		if err != nil {
+			return numa.CPUSet{}, err
+		}
+
+		if !set.Contains(n.ID) {
+			continue
+		}
+		for _, cpu := range n.CPU {
+			cpus.Add(cpu)
+		}
+	}
+	return cpus, nil
+}
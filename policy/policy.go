@@ -0,0 +1,83 @@
+// Package policy applies NUMA memory policies with Linux mempolicy system calls.
+// AllocOnNode and Free are available on Linux and Windows, SetMemPolicy
+// and GetMemPolicy on Linux and FreeBSD using domainsets.
+package policy
+
+import "strconv"
+
+// Mode is memory policy mode.
+type Mode int
+
+// Memory policy modes, see set_mempolicy(2).
+const (
+	// Default allocates memory on the node of the CPU that triggered the allocation.
+	Default Mode = iota
+	// Preferred allocates memory on the preferred node, falling back to other nodes.
+	Preferred
+	// Bind restricts allocations to the given nodes.
+	Bind
+	// Interleave interleaves allocations across the given nodes.
+	Interleave
+	// Local allocates memory on the node of the CPU that triggered the allocation,
+	// unlike Default it isn't overridden by process policy.
+	Local
+	// PreferredMany allocates memory on the nearest of preferred nodes, falling back
+	// to other nodes. It requires Linux 5.15, on older kernels Preferred with the
+	// lowest node of the set is used instead.
+	PreferredMany
+	// WeightedInterleave interleaves allocations across the given nodes proportionally
+	// to node weights, see InterleaveWeights. It requires Linux 6.9, on older kernels
+	// Interleave is used instead.
+	WeightedInterleave
+)
+
+// Mode flags which could be combined with mode.
+const (
+	// StaticNodes makes nodemask non-relative to cpuset changes.
+	StaticNodes Mode = 1 << 15
+	// RelativeNodes makes nodemask relative to the cpuset allowed nodes.
+	RelativeNodes Mode = 1 << 14
+	// NumaBalancing enables NUMA balancing for Bind policy.
+	NumaBalancing Mode = 1 << 13
+
+	modeFlags = StaticNodes | RelativeNodes | NumaBalancing
+)
+
+// Base returns mode without mode flags.
+func (m Mode) Base() Mode {
+	return m &^ modeFlags
+}
+
+func (m Mode) String() string {
+	var s string
+	switch m.Base() {
+	case Default:
+		s = "default"
+	case Preferred:
+		s = "preferred"
+	case Bind:
+		s = "bind"
+	case Interleave:
+		s = "interleave"
+	case Local:
+		s = "local"
+	case PreferredMany:
+		s = "preferred-many"
+	case WeightedInterleave:
+		s = "weighted-interleave"
+	default:
+		s = "mode(" + strconv.Itoa(int(m.Base())) + ")"
+	}
+
+	if m&StaticNodes != 0 {
+		s += "|static"
+	}
+	if m&RelativeNodes != 0 {
+		s += "|relative"
+	}
+	if m&NumaBalancing != 0 {
+		s += "|balancing"
+	}
+
+	return s
+}
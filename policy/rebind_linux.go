@@ -0,0 +1,74 @@
+package policy
+
+import (
+	"context"
+	"time"
+
+	"github.com/oneumyvakin/numa"
+)
+
+// RebindConfig configures AutoRebind.
+type RebindConfig struct {
+	Drift numa.DriftConfig
+	// MinInterval is minimal time between corrections, 1 minute if zero.
+	MinInterval time.Duration
+	// DryRun reports corrections without applying them.
+	DryRun bool
+}
+
+// RebindEvent represent drift event and correction taken for it.
+type RebindEvent struct {
+	numa.DriftEvent
+	// Corrected is true if affinity and memory were re-applied,
+	// or would be in dry-run mode.
+	Corrected bool
+	DryRun    bool
+	// RateLimited is true if correction was skipped because previous
+	// correction happened less than MinInterval ago.
+	RateLimited bool
+	// CorrectionErr is error of applying correction.
+	CorrectionErr error
+}
+
+// AutoRebind watches process pid for drift from nodes and when it drifts
+// restricts its threads to CPUs of nodes and migrates its memory to nodes.
+// Events are sent to the returned channel which is closed when ctx is done
+// or process can't be sampled anymore.
+func AutoRebind(ctx context.Context, pid int, nodes numa.NodeSet, cfg RebindConfig) <-chan RebindEvent {
+	if cfg.MinInterval <= 0 {
+		cfg.MinInterval = time.Minute
+	}
+	cfg.Drift.Repeat = true
+
+	p := Policy{CPUNodes: nodes, MemNodes: nodes}
+
+	events := make(chan RebindEvent)
+	go func() {
+		defer close(events)
+
+		var lastCorrection time.Time
+		for e := range numa.WatchDrift(ctx, pid, nodes, cfg.Drift) {
+			r := RebindEvent{DriftEvent: e, DryRun: cfg.DryRun}
+
+			if e.Err == nil && e.Drifting {
+				if !lastCorrection.IsZero() && e.Time.Sub(lastCorrection) < cfg.MinInterval {
+					r.RateLimited = true
+				} else {
+					lastCorrection = e.Time
+					r.Corrected = true
+					if !cfg.DryRun {
+						r.CorrectionErr = p.Apply(pid)
+					}
+				}
+			}
+
+			select {
+			case events <- r:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events
+}
@@ -0,0 +1,47 @@
+package policy
+
+import (
+	"os"
+	"runtime"
+	"unsafe"
+)
+
+// residencyBatch is number of pages queried with one move_pages call.
+const residencyBatch = 1024
+
+// SliceResidency returns number of pages backing b keyed by node.
+// Pages which are not faulted in yet are not counted.
+func SliceResidency(b []byte) (map[int]int, error) {
+	residency := make(map[int]int)
+	if len(b) == 0 {
+		return residency, nil
+	}
+
+	pageSize := uintptr(os.Getpagesize())
+	start := uintptr(unsafe.Pointer(&b[0])) &^ (pageSize - 1)
+	end := uintptr(unsafe.Pointer(&b[0])) + uintptr(len(b))
+
+	pages := make([]uintptr, 0, residencyBatch)
+	for addr := start; addr < end; addr += pageSize {
+		pages = append(pages, addr)
+		if len(pages) < residencyBatch && addr+pageSize < end {
+			continue
+		}
+
+		nodes, err := QueryPages(0, pages)
+		if err != nil {
+			return nil, err
+		}
+		for _, node := range nodes {
+			if node >= 0 {
+				residency[node]++
+			}
+		}
+		pages = pages[:0]
+	}
+
+	// Pages must not be freed while kernel is queried.
+	runtime.KeepAlive(b)
+
+	return residency, nil
+}
@@ -0,0 +1,92 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/oneumyvakin/numa"
+)
+
+// State represent NUMA configuration of the calling thread,
+// similar to `numactl --show`.
+type State struct {
+	Mode Mode
+	// Nodes are policy nodes, e.g. interleave or bind mask.
+	Nodes numa.NodeSet
+	// PreferredNode is -1 unless Mode is Preferred.
+	PreferredNode int
+	// PhysCPUBind are CPUs the thread is allowed to run on.
+	PhysCPUBind numa.CPUSet
+	// CPUBind are nodes of PhysCPUBind CPUs.
+	CPUBind numa.NodeSet
+	// MemBind are nodes the thread is allowed to allocate memory on.
+	MemBind numa.NodeSet
+}
+
+// ShowPolicy returns NUMA configuration of the calling thread.
+func ShowPolicy() (State, error) {
+	mode, nodes, err := GetMemPolicy()
+	if err != nil {
+		return State{}, err
+	}
+
+	s := State{Mode: mode, Nodes: nodes, PreferredNode: -1}
+	if mode.Base() == Preferred {
+		if ids := nodes.IDs(); len(ids) > 0 {
+			s.PreferredNode = ids[0]
+		}
+	}
+
+	if s.PhysCPUBind, err = numa.GetThreadAffinity(0); err != nil {
+		return State{}, err
+	}
+
+	// Only node cpulists are read, memory of nodes isn't needed.
+	cpuNodes, err := numa.CPUToNodeMap()
+	if err != nil {
+		return State{}, err
+	}
+	for _, cpu := range s.PhysCPUBind.IDs() {
+		if node, ok := cpuNodes[cpu]; ok {
+			s.CPUBind.Add(node)
+		}
+	}
+
+	var unused int32
+	mask := make([]uintptr, maxNodes/wordBits)
+	if err := getMemPolicy(&unused, mask, 0, mpolFMemsAllowed); err != nil {
+		return State{}, fmt.Errorf("get_mempolicy allowed nodes: %w", err)
+	}
+	s.MemBind = numa.NodeSetFromUintptrs(mask)
+
+	return s, nil
+}
+
+// String returns state formatted like `numactl --show`.
+func (s State) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "policy: %s\n", s.Mode)
+	if s.PreferredNode >= 0 {
+		fmt.Fprintf(&b, "preferred node: %d\n", s.PreferredNode)
+	} else {
+		b.WriteString("preferred node: current\n")
+	}
+	fmt.Fprintf(&b, "physcpubind: %s\n", joinIDs(s.PhysCPUBind.IDs()))
+	fmt.Fprintf(&b, "cpubind: %s\n", joinIDs(s.CPUBind.IDs()))
+	fmt.Fprintf(&b, "nodebind: %s\n", joinIDs(s.CPUBind.IDs()))
+	fmt.Fprintf(&b, "membind: %s\n", joinIDs(s.MemBind.IDs()))
+	if s.Mode.Base() == Interleave || s.Mode.Base() == WeightedInterleave {
+		fmt.Fprintf(&b, "interleavemask: %s\n", joinIDs(s.Nodes.IDs()))
+	}
+
+	return b.String()
+}
+
+func joinIDs(ids []int) string {
+	s := make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = fmt.Sprint(id)
+	}
+	return strings.Join(s, " ")
+}
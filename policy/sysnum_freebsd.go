@@ -0,0 +1,7 @@
+package policy
+
+// System calls missing in syscall package, numbers are the same on all architectures.
+const (
+	sysCpusetGetdomain = 561
+	sysCpusetSetdomain = 562
+)
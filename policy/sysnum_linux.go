@@ -0,0 +1,7 @@
+//go:build linux && !arm
+
+package policy
+
+import "syscall"
+
+const sysMigratePages = syscall.SYS_MIGRATE_PAGES
@@ -0,0 +1,4 @@
+package policy
+
+// syscall package doesn't define migrate_pages for arm.
+const sysMigratePages = 400
@@ -0,0 +1,59 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const weightsPath = "/sys/kernel/mm/mempolicy/weighted_interleave"
+
+// InterleaveWeights returns WeightedInterleave weights keyed by node ID.
+func InterleaveWeights() (map[int]int, error) {
+	dir, err := os.ReadDir(weightsPath)
+	if err != nil {
+		return nil, unsupportedErr(err)
+	}
+
+	weights := make(map[int]int)
+	for _, i := range dir {
+		if !strings.HasPrefix(i.Name(), "node") {
+			continue
+		}
+
+		node, err := strconv.Atoi(strings.TrimPrefix(i.Name(), "node"))
+		if err != nil {
+			continue
+		}
+
+		f, err := os.ReadFile(filepath.Join(weightsPath, i.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		weight, err := strconv.Atoi(strings.TrimSpace(string(f)))
+		if err != nil {
+			return nil, fmt.Errorf("convert weight of %s %q: %w", i.Name(), string(f), err)
+		}
+		weights[node] = weight
+	}
+
+	return weights, nil
+}
+
+// SetInterleaveWeight sets WeightedInterleave weight of node in range 1-255.
+// It requires root.
+func SetInterleaveWeight(node, weight int) error {
+	if weight < 1 || weight > 255 {
+		return fmt.Errorf("weight %d of node %d is out of range 1-255", weight, node)
+	}
+
+	path := filepath.Join(weightsPath, fmt.Sprintf("node%d", node))
+	if err := os.WriteFile(path, []byte(strconv.Itoa(weight)), 0); err != nil {
+		return fmt.Errorf("set interleave weight of node %d: %w", node, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+// Package prometheus provides Prometheus collector of NUMA nodes metrics.
+package prometheus
+
+import (
+	"strconv"
+
+	"github.com/oneumyvakin/numa"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "numa"
+
+// Collector exports per node memory gauges and numastat counters labeled by node.
+type Collector struct {
+	opts []numa.Option
+
+	memTotal     *prometheus.Desc
+	memFree      *prometheus.Desc
+	memAvailable *prometheus.Desc
+	numastat     *prometheus.Desc
+	scrapeError  *prometheus.Desc
+}
+
+// NewCollector returns Collector reading nodes using opts on every scrape.
+func NewCollector(opts ...numa.Option) *Collector {
+	return &Collector{
+		opts: opts,
+		memTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "node", "mem_total_bytes"),
+			"Total memory of NUMA node.",
+			[]string{"node"}, nil,
+		),
+		memFree: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "node", "mem_free_bytes"),
+			"Free memory of NUMA node.",
+			[]string{"node"}, nil,
+		),
+		memAvailable: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "node", "mem_available_bytes"),
+			"Estimated available memory of NUMA node.",
+			[]string{"node"}, nil,
+		),
+		numastat: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "node", "numastat_pages_total"),
+			"NUMA node allocation counters from numastat.",
+			[]string{"node", "type"}, nil,
+		),
+		scrapeError: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "scrape_error"),
+			"1 if reading any NUMA node failed.",
+			nil, nil,
+		),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.memTotal
+	ch <- c.memFree
+	ch <- c.memAvailable
+	ch <- c.numastat
+	ch <- c.scrapeError
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	// Nodes read successfully are exported even if other nodes failed.
+	nodes, err := numa.GetNodes(c.opts...)
+	scrapeError := 0.0
+	if err != nil {
+		scrapeError = 1
+	}
+	ch <- prometheus.MustNewConstMetric(c.scrapeError, prometheus.GaugeValue, scrapeError)
+
+	for _, n := range nodes {
+		node := strconv.Itoa(n.ID)
+
+		ch <- prometheus.MustNewConstMetric(c.memTotal, prometheus.GaugeValue, float64(n.MemTotal), node)
+		ch <- prometheus.MustNewConstMetric(c.memFree, prometheus.GaugeValue, float64(n.MemFree), node)
+		ch <- prometheus.MustNewConstMetric(c.memAvailable, prometheus.GaugeValue, float64(n.MemAvailable), node)
+
+		for _, s := range []struct {
+			name  string
+			value uint64
+		}{
+			{"numa_hit", n.Stats.NumaHit},
+			{"numa_miss", n.Stats.NumaMiss},
+			{"numa_foreign", n.Stats.NumaForeign},
+			{"interleave_hit", n.Stats.InterleaveHit},
+			{"local_node", n.Stats.LocalNode},
+			{"other_node", n.Stats.OtherNode},
+		} {
+			ch <- prometheus.MustNewConstMetric(c.numastat, prometheus.CounterValue, float64(s.value), node, s.name)
+		}
+	}
+}
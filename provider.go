@@ -0,0 +1,173 @@
+package numa
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// TopologyProvider is a source of nodes, e.g. sysfs of the local machine,
+// operating system API, snapshot of a remote machine or mock in tests.
+// Readers use provider given by WithProvider instead of the system one.
+type TopologyProvider interface {
+	// Nodes returns all nodes. Nodes which can't be read are skipped
+	// and reported as NodeError joined into err.
+	Nodes(ctx context.Context) ([]Node, error)
+	// Distances returns NUMA distances between nodes,
+	// rows are in the same order as nodes returned by Nodes.
+	Distances(ctx context.Context) ([][]int, error)
+	// Stats returns numastat counters keyed by node ID.
+	Stats(ctx context.Context) (map[int]NodeStats, error)
+}
+
+// WithProvider makes GetNodes, GetNode, Nodes, NodeIDs, CPUToNodeMap,
+// NodeForCPU, GetDistanceMatrix and Node.Refresh read nodes from p. Options of sources
+// and fields, e.g. WithSysFS and WithFields, aren't applied to nodes of p.
+func WithProvider(p TopologyProvider) Option {
+	return func(o *options) {
+		o.provider = p
+	}
+}
+
+// SystemProvider returns provider reading sysfs and procfs given by opts,
+// or operating system API where sysfs isn't available, e.g. on Windows.
+// It's used by readers without WithProvider and could be wrapped by other
+// providers, e.g. caching ones. WithProvider in opts is ignored.
+func SystemProvider(opts ...Option) TopologyProvider {
+	o := newOptions(opts)
+	o.provider = nil
+	return systemProvider{o: o}
+}
+
+// topologyProvider returns provider given by WithProvider or the system one.
+func (o options) topologyProvider() TopologyProvider {
+	if o.provider != nil {
+		return o.provider
+	}
+	return systemProvider{o: o}
+}
+
+// providerNodes returns nodes of provider given by WithProvider or of
+// operating system backend, ok is false if nodes should be read from sysfs.
+func providerNodes(ctx context.Context, o options) ([]Node, bool, error) {
+	if o.provider != nil {
+		nodes, err := o.provider.Nodes(ctx)
+		return nodes, true, err
+	}
+	return platformNodes(o)
+}
+
+type systemProvider struct {
+	o options
+}
+
+func (p systemProvider) Nodes(ctx context.Context) ([]Node, error) {
+	return collectNodes(ctx, p.o)
+}
+
+func (p systemProvider) Distances(ctx context.Context) ([][]int, error) {
+	if nodes, ok, err := platformNodes(p.o); ok {
+		if err != nil {
+			return nil, err
+		}
+		return nodesDistances(nodes), nil
+	}
+
+	ids, err := sysfsNodeIDs(p.o)
+	if err != nil {
+		return nil, noNUMAErr(err)
+	}
+
+	matrix := make([][]int, 0, len(ids))
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		distances, err := parseDistance(p.o.sys, path.Join("devices/system/node", fmt.Sprintf("node%d", id), "distance"))
+		if err != nil {
+			return nil, &NodeError{Node: id, Err: fmt.Errorf("parse distance: %w", err)}
+		}
+		matrix = append(matrix, distances)
+	}
+
+	return matrix, nil
+}
+
+func (p systemProvider) Stats(ctx context.Context) (map[int]NodeStats, error) {
+	if nodes, ok, err := platformNodes(p.o); ok {
+		if err != nil {
+			return nil, err
+		}
+		return nodesStats(nodes), nil
+	}
+
+	ids, err := sysfsNodeIDs(p.o)
+	if err != nil {
+		return nil, noNUMAErr(err)
+	}
+
+	stats := make(map[int]NodeStats, len(ids))
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		s, err := parseNumaStat(p.o, path.Join("devices/system/node", fmt.Sprintf("node%d", id), "numastat"))
+		if err != nil {
+			return nil, &NodeError{Node: id, Err: fmt.Errorf("parse numastat: %w", err)}
+		}
+		stats[id] = s
+	}
+
+	return stats, nil
+}
+
+// nodesDistances returns distance matrix of nodes for providers
+// reading distances together with nodes.
+func nodesDistances(nodes []Node) [][]int {
+	matrix := make([][]int, 0, len(nodes))
+	for _, n := range nodes {
+		matrix = append(matrix, n.Distances)
+	}
+	return matrix
+}
+
+// nodesStats returns numastat counters of nodes keyed by node ID.
+func nodesStats(nodes []Node) map[int]NodeStats {
+	stats := make(map[int]NodeStats, len(nodes))
+	for _, n := range nodes {
+		stats[n.ID] = n.Stats
+	}
+	return stats
+}
+
+// sysfsNodeIDs returns IDs of node directories in ascending order, the same
+// as order of distance columns. Directories are listed in lexical order,
+// e.g. node10 before node2.
+func sysfsNodeIDs(o options) ([]int, error) {
+	dir, err := fs.ReadDir(o.sys, "devices/system/node")
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int
+	for _, i := range dir {
+		if !i.IsDir() || !strings.HasPrefix(i.Name(), "node") {
+			continue
+		}
+
+		id, err := strconv.Atoi(strings.TrimPrefix(i.Name(), "node"))
+		if err != nil {
+			return nil, fmt.Errorf("convert node %q: %w", i.Name(), err)
+		}
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	return ids, nil
+}
@@ -0,0 +1,44 @@
+package numa
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSysfsNodeIDsNumericOrder(t *testing.T) {
+	fsys := fstest.MapFS{}
+	for _, name := range []string{"node0", "node1", "node10", "node2", "node11"} {
+		fsys["devices/system/node/"+name+"/cpulist"] = &fstest.MapFile{}
+	}
+	fsys["devices/system/node/online"] = &fstest.MapFile{Data: []byte("0-2,10-11\n")}
+
+	ids, err := sysfsNodeIDs(newOptions([]Option{WithSysFS(fsys)}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{0, 1, 2, 10, 11}; !slices.Equal(ids, want) {
+		t.Errorf("ids = %v, want %v", ids, want)
+	}
+}
+
+func TestNodeForCPUProvider(t *testing.T) {
+	p := &sequenceProvider{sequence: [][]Node{{
+		{ID: 0, CPU: []int{0, 1}},
+		{ID: 3, CPU: []int{2, 3}},
+		{ID: 4},
+	}}}
+
+	node, err := NodeForCPU(3, WithProvider(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node != 3 {
+		t.Errorf("NodeForCPU(3) = %d, want 3", node)
+	}
+
+	if _, err := NodeForCPU(8, WithProvider(p)); !errors.Is(err, ErrNodeNotFound) {
+		t.Errorf("NodeForCPU(8) error = %v, want %v", err, ErrNodeNotFound)
+	}
+}
@@ -0,0 +1,213 @@
+package numa
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// PSI represent pressure stall information averages in percents
+// and total stall time in microseconds.
+type PSI struct {
+	Avg10  float64
+	Avg60  float64
+	Avg300 float64
+	Total  uint64
+}
+
+// PressureStats represent "some" and "full" lines of pressure file.
+// Full is zero for CPU pressure on kernels before 5.13.
+type PressureStats struct {
+	Some PSI
+	Full PSI
+}
+
+// CgroupPressure represent memory and CPU pressure of cgroup pinned to single NUMA node.
+type CgroupPressure struct {
+	// Path is cgroup path relative to the unified hierarchy root, e.g. "/system.slice/app.service".
+	Path   string
+	Node   int
+	Memory PressureStats
+	CPU    PressureStats
+}
+
+// GetNodePressure returns pressure of cgroup v2 cgroups pinned by cpuset.mems to single node.
+// Cgroups without pressure files, e.g. when kernel booted with psi=0, are skipped.
+func GetNodePressure() ([]CgroupPressure, error) {
+	mounts, err := readCgroupMounts()
+	if err != nil {
+		return nil, err
+	}
+
+	var root string
+	for _, m := range mounts {
+		if m.fsType == "cgroup2" {
+			root = m.mountPoint
+			break
+		}
+	}
+	if root == "" {
+		return nil, fmt.Errorf("unified hierarchy: %w", errNoCgroup)
+	}
+
+	// Root cgroup has all memory nodes, cgroups having only them aren't pinned.
+	all, _, err := effectiveMems(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var pressures []CgroupPressure
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Cgroups could be removed while walking.
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+
+		node, ok, err := pinnedNode(path, all)
+		if err != nil || !ok {
+			return err
+		}
+
+		memory, err := ParsePressure(filepath.Join(path, "memory.pressure"))
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("parse memory pressure: %w", err)
+		}
+
+		cpu, err := ParsePressure(filepath.Join(path, "cpu.pressure"))
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("parse cpu pressure: %w", err)
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		pressures = append(pressures, CgroupPressure{
+			Path:   filepath.Join("/", rel),
+			Node:   node,
+			Memory: memory,
+			CPU:    cpu,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pressures, nil
+}
+
+// pinnedNode returns node of cgroup cpuset.mems.effective if it contains
+// single node and the cgroup is restricted, i.e. all isn't single node.
+// Effective nodes are used as cpuset.mems is empty in cgroups inheriting
+// nodes of parent, e.g. children of node-pinned cgroup.
+func pinnedNode(dir string, all NodeSet) (int, bool, error) {
+	mems, ok, err := effectiveMems(dir)
+	if err != nil || !ok {
+		return 0, false, err
+	}
+	if mems.Len() != 1 || mems.Equal(all) {
+		return 0, false, nil
+	}
+
+	return mems.IDs()[0], true, nil
+}
+
+// effectiveMems returns cpuset.mems.effective of cgroup, false if cpuset
+// controller isn't enabled for the cgroup.
+func effectiveMems(dir string) (NodeSet, bool, error) {
+	f, err := os.ReadFile(filepath.Join(dir, "cpuset.mems.effective"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return NodeSet{}, false, nil
+		}
+		return NodeSet{}, false, err
+	}
+
+	mems, err := ParseNodeSet(strings.TrimSpace(string(f)))
+	if err != nil {
+		return NodeSet{}, false, fmt.Errorf("parse %s cpuset.mems.effective: %w", dir, err)
+	}
+
+	return mems, true, nil
+}
+
+// ParsePressure parses PSI file, e.g. /proc/pressure/memory or cgroup memory.pressure.
+func ParsePressure(path string) (PressureStats, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return PressureStats{}, err
+	}
+	defer f.Close()
+
+	var p PressureStats
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// some avg10=0.00 avg60=0.00 avg300=0.00 total=0
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 5 {
+			continue
+		}
+
+		var psi *PSI
+		switch fields[0] {
+		case "some":
+			psi = &p.Some
+		case "full":
+			psi = &p.Full
+		default:
+			continue
+		}
+
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				return PressureStats{}, fmt.Errorf("invalid format %q", scanner.Text())
+			}
+
+			if key == "total" {
+				psi.Total, err = strconv.ParseUint(value, 10, 64)
+				if err != nil {
+					return PressureStats{}, fmt.Errorf("convert %s %q: %w", key, value, err)
+				}
+				continue
+			}
+
+			avg, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return PressureStats{}, fmt.Errorf("convert %s %q: %w", key, value, err)
+			}
+			switch key {
+			case "avg10":
+				psi.Avg10 = avg
+			case "avg60":
+				psi.Avg60 = avg
+			case "avg300":
+				psi.Avg300 = avg
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return PressureStats{}, err
+	}
+
+	return p, nil
+}
@@ -0,0 +1,48 @@
+package numa
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPinnedNode(t *testing.T) {
+	tests := []struct {
+		name      string
+		mems      string
+		effective string
+		all       NodeSet
+		node      int
+		pinned    bool
+	}{
+		{name: "pinned", mems: "1\n", effective: "1\n", all: NewNodeSet(0, 1), node: 1, pinned: true},
+		{name: "inherited", mems: "\n", effective: "1\n", all: NewNodeSet(0, 1), node: 1, pinned: true},
+		{name: "several nodes", mems: "\n", effective: "0-1\n", all: NewNodeSet(0, 1)},
+		{name: "single node machine", mems: "\n", effective: "0\n", all: NewNodeSet(0)},
+		{name: "no cpuset controller", all: NewNodeSet(0, 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.effective != "" {
+				writeFile(t, filepath.Join(dir, "cpuset.mems"), tt.mems)
+				writeFile(t, filepath.Join(dir, "cpuset.mems.effective"), tt.effective)
+			}
+
+			node, pinned, err := pinnedNode(dir, tt.all)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pinned != tt.pinned || node != tt.node {
+				t.Errorf("pinnedNode() = %d, %t, want %d, %t", node, pinned, tt.node, tt.pinned)
+			}
+		})
+	}
+}
+
+func writeFile(t *testing.T, name, data string) {
+	t.Helper()
+	if err := os.WriteFile(name, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,75 @@
+package numa
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"time"
+)
+
+// CounterSample represent numastat and vmstat counters of NUMA node at a point in time.
+type CounterSample struct {
+	Time time.Time
+	Node int
+	// Counters are keyed by name as it appears in numastat and vmstat files,
+	// e.g. "numa_miss" or "pgpromote_success".
+	Counters map[string]uint64
+}
+
+// SampleCounters returns current numastat and vmstat counters of NUMA node.
+func SampleCounters(nodeID int, opts ...Option) (CounterSample, error) {
+	o := newOptions(opts)
+	nodePath := path.Join("devices/system/node", fmt.Sprintf("node%d", nodeID))
+
+	counters, err := parseKeyValues(o, path.Join(nodePath, "vmstat"))
+	if err != nil {
+		return CounterSample{}, fmt.Errorf("parse vmstat: %w", err)
+	}
+
+	numastat, err := parseKeyValues(o, path.Join(nodePath, "numastat"))
+	if err != nil {
+		return CounterSample{}, fmt.Errorf("parse numastat: %w", err)
+	}
+	for k, v := range numastat {
+		counters[k] = v
+	}
+
+	return CounterSample{Time: time.Now(), Node: nodeID, Counters: counters}, nil
+}
+
+// Rates returns per-second rates of counters between two samples of the same node.
+// Counter which decreased is considered wrapped at 32 bits if its previous value
+// fits in 32 bits and at 64 bits otherwise. Gauges such as nr_free_pages have
+// meaningless rates. Counters missing in either sample are skipped.
+func Rates(previous, current CounterSample) (map[string]float64, error) {
+	if previous.Node != current.Node {
+		return nil, fmt.Errorf("samples of different nodes %d and %d", previous.Node, current.Node)
+	}
+
+	seconds := current.Time.Sub(previous.Time).Seconds()
+	if seconds <= 0 {
+		return nil, fmt.Errorf("sample at %s is not after %s", current.Time, previous.Time)
+	}
+
+	rates := make(map[string]float64, len(current.Counters))
+	for name, cur := range current.Counters {
+		prev, ok := previous.Counters[name]
+		if !ok {
+			continue
+		}
+		rates[name] = float64(counterDelta(prev, cur)) / seconds
+	}
+
+	return rates, nil
+}
+
+func counterDelta(previous, current uint64) uint64 {
+	if current >= previous {
+		return current - previous
+	}
+	if previous <= math.MaxUint32 {
+		return current + (math.MaxUint32 - previous) + 1
+	}
+	// Unsigned subtraction wraps at 64 bits.
+	return current - previous
+}
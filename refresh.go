@@ -0,0 +1,95 @@
+package numa
+
+import (
+	"context"
+	"fmt"
+	"path"
+)
+
+// Refresh re-reads volatile node information in place: meminfo, available
+// memory, numastat, hugepages and zones. Topology, e.g. CPUs and distances,
+// is kept. Options should be the same as used to read the node, fields
+// skipped with WithFields are reset to zero values.
+func (n *Node) Refresh(opts ...Option) error {
+	return NewCollector(opts...).Refresh(n)
+}
+
+func refreshNode(o options, n *Node) error {
+	if nodes, ok, err := providerNodes(context.Background(), o); ok {
+		for _, p := range nodes {
+			if p.ID == n.ID {
+				n.MemInfo, n.MemApproximate = p.MemInfo, p.MemApproximate
+				n.Stats, n.Hugepages, n.Zones = p.Stats, p.Hugepages, p.Zones
+				n.MemTotal, n.MemFree, n.MemAvailable = p.MemTotal, p.MemFree, p.MemAvailable
+				return nil
+			}
+		}
+		if err != nil {
+			return err
+		}
+		return ErrNodeNotFound
+	}
+
+	nodePath := path.Join("devices/system/node", fmt.Sprintf("node%d", n.ID))
+
+	var meminfo MemInfo
+	var approximate bool
+	var err error
+	if o.fields.has(FieldMemInfo) {
+		if meminfo, approximate, err = readNodeMemInfo(o, nodePath); err != nil {
+			return fmt.Errorf("parse meminfo: %w", err)
+		}
+	}
+
+	var stats NodeStats
+	if o.fields.has(FieldStats) {
+		if stats, err = parseNumaStat(o, path.Join(nodePath, "numastat")); err != nil {
+			return fmt.Errorf("parse numastat: %w", err)
+		}
+	}
+
+	var hugepages map[uint64]Hugepages
+	if o.fields.has(FieldHugepages) {
+		if hugepages, err = parseHugepages(o.sys, path.Join(nodePath, "hugepages")); err != nil {
+			return fmt.Errorf("parse hugepages: %w", err)
+		}
+	}
+
+	// Without zoneinfo available memory is estimated without watermarks.
+	var zones []Zone
+	zonesErr := errNotRead
+	if o.fields.has(FieldZones) {
+		zones, zonesErr = readZoneInfo(o)
+		if zonesErr != nil {
+			o.warn("zoneinfo not readable, available memory estimated without watermarks", "err", zonesErr)
+		}
+	}
+	zonesOfNode := nodeZones(zones, n.ID)
+
+	// Memory of node outside of cpuset mems is cleared like by WithCgroupFiltering.
+	outsideCpuset := false
+	if o.cgroupFiltering {
+		mems, err := EffectiveNodes()
+		if err != nil {
+			return fmt.Errorf("read effective nodes: %w", err)
+		}
+		outsideCpuset = !mems.Contains(n.ID)
+	}
+
+	n.MemInfo = meminfo
+	n.MemApproximate = approximate
+	n.Stats = stats
+	n.Hugepages = hugepages
+	n.Zones = zonesOfNode
+	n.MemTotal, n.MemFree, n.MemAvailable = 0, 0, 0
+	if o.fields.has(FieldMemInfo) {
+		n.MemTotal = meminfo.MemTotal
+		n.MemFree = meminfo.MemFree
+		n.MemAvailable = nodeAvailableMemory(meminfo, approximate, zonesOfNode, zonesErr)
+	}
+	if outsideCpuset {
+		n.clearMemory()
+	}
+
+	return nil
+}
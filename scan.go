@@ -0,0 +1,105 @@
+package numa
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ProcessUsage represent memory of process in bytes keyed by node ID.
+type ProcessUsage struct {
+	PID   int
+	Name  string
+	Nodes map[int]uint64
+}
+
+// SystemUsage represent memory of all processes per node,
+// similar to `numastat -p` for every process.
+type SystemUsage struct {
+	// Processes are sorted by PID.
+	Processes []ProcessUsage
+	// Nodes is total memory of all processes in bytes keyed by node ID.
+	Nodes map[int]uint64
+}
+
+// ScanProcesses reads numa_maps of all processes using at most concurrency
+// goroutines, concurrency less than 1 means a single goroutine. Processes
+// which exited or whose numa_maps can't be read are skipped.
+// It stops and returns context error when ctx is done.
+func ScanProcesses(ctx context.Context, concurrency int) (SystemUsage, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	dir, err := os.ReadDir("/proc")
+	if err != nil {
+		return SystemUsage{}, err
+	}
+
+	pids := make(chan int)
+	results := make(chan ProcessUsage)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for pid := range pids {
+				maps, err := ParseNumaMapsContext(ctx, pid)
+				if err != nil {
+					continue
+				}
+
+				comm, _ := os.ReadFile(procPath(pid, "comm"))
+
+				select {
+				case results <- ProcessUsage{PID: pid, Name: strings.TrimSpace(string(comm)), Nodes: nodeUsage(maps)}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(pids)
+		for _, i := range dir {
+			pid, err := strconv.Atoi(i.Name())
+			if err != nil {
+				continue
+			}
+
+			select {
+			case pids <- pid:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	usage := SystemUsage{Nodes: make(map[int]uint64)}
+	for p := range results {
+		usage.Processes = append(usage.Processes, p)
+		for node, bytes := range p.Nodes {
+			usage.Nodes[node] += bytes
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return SystemUsage{}, err
+	}
+
+	sort.Slice(usage.Processes, func(a, b int) bool {
+		return usage.Processes[a].PID < usage.Processes[b].PID
+	})
+
+	return usage, nil
+}
@@ -0,0 +1,79 @@
+package numa
+
+import "sort"
+
+// Strategy represent how PickN selects nodes.
+type Strategy int
+
+const (
+	// MostAvailable picks nodes with the most available memory.
+	MostAvailable Strategy = iota
+	// MostCPUs picks nodes with the most CPUs, ties are broken by available memory.
+	MostCPUs
+	// Closest picks node with the most available memory and then nodes
+	// with the smallest distance to it.
+	Closest
+)
+
+// SortByAvailable returns copy of nodes sorted by available memory in descending order.
+// Nodes with equal available memory keep their order.
+func (l NodeList) SortByAvailable() NodeList {
+	sorted := make(NodeList, len(l))
+	copy(sorted, l)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		return sorted[a].MemAvailable > sorted[b].MemAvailable
+	})
+	return sorted
+}
+
+// BestNodeFor returns node with the most available memory having at least bytes available.
+// False is returned if no node has enough memory.
+func (l NodeList) BestNodeFor(bytes uint64) (Node, bool) {
+	var best Node
+	var found bool
+	for _, n := range l {
+		if n.MemAvailable < bytes {
+			continue
+		}
+		if !found || n.MemAvailable > best.MemAvailable {
+			best, found = n, true
+		}
+	}
+	return best, found
+}
+
+// PickN returns up to n nodes selected by strategy.
+func (l NodeList) PickN(n int, strategy Strategy) NodeList {
+	if n <= 0 || len(l) == 0 {
+		return nil
+	}
+	if n > len(l) {
+		n = len(l)
+	}
+
+	sorted := l.SortByAvailable()
+	switch strategy {
+	case MostCPUs:
+		sort.SliceStable(sorted, func(a, b int) bool {
+			return len(sorted[a].CPU) > len(sorted[b].CPU)
+		})
+	case Closest:
+		first := sorted[0]
+		rest := sorted[1:]
+		sort.SliceStable(rest, func(a, b int) bool {
+			return distanceTo(first, rest[a].ID) < distanceTo(first, rest[b].ID)
+		})
+	}
+
+	return sorted[:n]
+}
+
+// distanceTo returns distance from node to node ID, distances of unknown
+// nodes are considered the largest.
+func distanceTo(n Node, id int) int {
+	d, ok := n.Distance(id)
+	if !ok {
+		return int(^uint(0) >> 1)
+	}
+	return d
+}
@@ -0,0 +1,101 @@
+package numa
+
+import (
+	"slices"
+	"testing"
+)
+
+func nodeIDs(l NodeList) []int {
+	ids := make([]int, 0, len(l))
+	for _, n := range l {
+		ids = append(ids, n.ID)
+	}
+	return ids
+}
+
+func TestSortByAvailable(t *testing.T) {
+	tests := []struct {
+		name  string
+		nodes NodeList
+		want  []int
+	}{
+		{"empty", nil, []int{}},
+		{"descending", NodeList{{ID: 0, MemAvailable: 1}, {ID: 1, MemAvailable: 3}, {ID: 2, MemAvailable: 2}}, []int{1, 2, 0}},
+		{"stable", NodeList{{ID: 0, MemAvailable: 2}, {ID: 1, MemAvailable: 2}, {ID: 2, MemAvailable: 5}}, []int{2, 0, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := nodeIDs(tt.nodes)
+			if got := nodeIDs(tt.nodes.SortByAvailable()); !slices.Equal(got, tt.want) {
+				t.Errorf("SortByAvailable() = %v, want %v", got, tt.want)
+			}
+			if got := nodeIDs(tt.nodes); !slices.Equal(got, original) {
+				t.Errorf("nodes modified to %v, want %v", got, original)
+			}
+		})
+	}
+}
+
+func TestBestNodeFor(t *testing.T) {
+	nodes := NodeList{{ID: 0, MemAvailable: 100}, {ID: 1, MemAvailable: 300}, {ID: 2, MemAvailable: 200}}
+
+	tests := []struct {
+		name  string
+		bytes uint64
+		want  int
+		found bool
+	}{
+		{"zero", 0, 1, true},
+		{"fits all", 100, 1, true},
+		{"exact", 300, 1, true},
+		{"too large", 301, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, found := nodes.BestNodeFor(tt.bytes)
+			if found != tt.found {
+				t.Fatalf("BestNodeFor(%d) found = %t, want %t", tt.bytes, found, tt.found)
+			}
+			if found && n.ID != tt.want {
+				t.Errorf("BestNodeFor(%d) = node%d, want node%d", tt.bytes, n.ID, tt.want)
+			}
+		})
+	}
+}
+
+func TestPickN(t *testing.T) {
+	nodes := NodeList{
+		{ID: 0, MemAvailable: 100, CPU: []int{0, 1, 2, 3}, Distances: []int{10, 40, 30}, DistanceNodes: []int{0, 2, 5}},
+		{ID: 2, MemAvailable: 300, CPU: []int{4}, Distances: []int{40, 10, 20}, DistanceNodes: []int{0, 2, 5}},
+		{ID: 5, MemAvailable: 200, CPU: []int{5, 6}, Distances: []int{30, 20, 10}, DistanceNodes: []int{0, 2, 5}},
+	}
+
+	tests := []struct {
+		name     string
+		n        int
+		strategy Strategy
+		want     []int
+	}{
+		{"none", 0, MostAvailable, nil},
+		{"negative", -1, MostAvailable, nil},
+		{"most available", 2, MostAvailable, []int{2, 5}},
+		{"more than nodes", 5, MostAvailable, []int{2, 5, 0}},
+		{"most cpus", 2, MostCPUs, []int{0, 5}},
+		// Distances of sparse node IDs are taken by DistanceNodes, not by index.
+		{"closest sparse ids", 3, Closest, []int{2, 5, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nodes.PickN(tt.n, tt.strategy)
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("PickN(%d) = %v, want nil", tt.n, nodeIDs(got))
+				}
+				return
+			}
+			if ids := nodeIDs(got); !slices.Equal(ids, tt.want) {
+				t.Errorf("PickN(%d) = %v, want %v", tt.n, ids, tt.want)
+			}
+		})
+	}
+}
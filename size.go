@@ -0,0 +1,49 @@
+package numa
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var byteUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+// FormatBytes returns human-readable size in binary units, e.g. "251.6 GiB".
+func FormatBytes(bytes uint64) string {
+	if bytes < 1024 {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	v := float64(bytes)
+	unit := 0
+	for v >= 1024 && unit < len(byteUnits)-1 {
+		v /= 1024
+		unit++
+	}
+
+	return fmt.Sprintf("%.1f %s", v, byteUnits[unit])
+}
+
+// ParseBytes parses size returned by FormatBytes. Sizes which were rounded
+// by FormatBytes are parsed as rounded.
+func ParseBytes(s string) (uint64, error) {
+	value, unit, ok := strings.Cut(strings.TrimSpace(s), " ")
+	if !ok {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("convert size %q: %w", s, err)
+	}
+
+	multiplier := uint64(1)
+	for _, u := range byteUnits {
+		if u == unit {
+			return uint64(v * float64(multiplier)), nil
+		}
+		multiplier *= 1024
+	}
+
+	return 0, fmt.Errorf("unknown unit of size %q", s)
+}
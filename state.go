@@ -0,0 +1,107 @@
+package numa
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+)
+
+// OnlineNodes returns nodes which are online.
+func OnlineNodes(opts ...Option) (NodeSet, error) {
+	return readNodeState(newOptions(opts), "online")
+}
+
+// PossibleNodes returns nodes which could be brought online.
+func PossibleNodes(opts ...Option) (NodeSet, error) {
+	return readNodeState(newOptions(opts), "possible")
+}
+
+// NodesWithCPU returns nodes which have CPUs.
+func NodesWithCPU(opts ...Option) (NodeSet, error) {
+	return readNodeState(newOptions(opts), "has_cpu")
+}
+
+// NodesWithMemory returns nodes which have memory.
+func NodesWithMemory(opts ...Option) (NodeSet, error) {
+	return readNodeState(newOptions(opts), "has_memory")
+}
+
+// NodesWithNormalMemory returns nodes which have normal (non-highmem, non-movable) memory.
+func NodesWithNormalMemory(opts ...Option) (NodeSet, error) {
+	return readNodeState(newOptions(opts), "has_normal_memory")
+}
+
+func readNodeState(o options, name string) (NodeSet, error) {
+	f, err := fs.ReadFile(o.sys, path.Join("devices/system/node", name))
+	if err != nil {
+		return NodeSet{}, noNUMAErr(err)
+	}
+
+	s, err := ParseNodeSet(string(f))
+	if err != nil {
+		return NodeSet{}, fmt.Errorf("parse %s: %w", name, err)
+	}
+
+	return s, nil
+}
+
+type nodeStates struct {
+	online          NodeSet
+	hasCPU          NodeSet
+	hasMemory       NodeSet
+	hasNormalMemory NodeSet
+}
+
+func readNodeStates(o options) (nodeStates, error) {
+	var states nodeStates
+	var err error
+
+	if states.online, err = readNodeState(o, "online"); err != nil {
+		return nodeStates{}, err
+	}
+	if states.hasCPU, err = readNodeState(o, "has_cpu"); err != nil {
+		return nodeStates{}, err
+	}
+	if states.hasMemory, err = readNodeState(o, "has_memory"); err != nil {
+		return nodeStates{}, err
+	}
+	if states.hasNormalMemory, err = readNodeState(o, "has_normal_memory"); err != nil {
+		return nodeStates{}, err
+	}
+
+	return states, nil
+}
+
+// NodeIDs returns sorted IDs of node directories without reading node files.
+func NodeIDs(opts ...Option) ([]int, error) {
+	o := newOptions(opts)
+
+	var ids []int
+	if nodes, ok, err := providerNodes(context.Background(), o); ok {
+		for _, n := range nodes {
+			ids = append(ids, n.ID)
+		}
+		sort.Ints(ids)
+		return ids, err
+	}
+
+	ids, err := sysfsNodeIDs(o)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Ints(ids)
+
+	return ids, nil
+}
+
+// NodeCount returns number of node directories without reading node files.
+func NodeCount(opts ...Option) (int, error) {
+	ids, err := NodeIDs(opts...)
+	if err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
@@ -0,0 +1,24 @@
+package numa
+
+import "runtime"
+
+// syntheticNode returns node 0 with all CPUs usable by the process
+// and memTotal bytes of memory, see WithSyntheticNode.
+func syntheticNode(memTotal uint64) Node {
+	cpus := make([]int, runtime.NumCPU())
+	for i := range cpus {
+		cpus[i] = i
+	}
+
+	return Node{
+		ID:              0,
+		CPU:             cpus,
+		MemTotal:        memTotal,
+		Distances:       []int{10},
+		MemoryTier:      -1,
+		Online:          true,
+		HasCPU:          true,
+		HasMemory:       memTotal > 0,
+		HasNormalMemory: memTotal > 0,
+	}
+}
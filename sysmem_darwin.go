@@ -0,0 +1,21 @@
+package numa
+
+import (
+	"encoding/binary"
+	"fmt"
+	"syscall"
+)
+
+// systemMemory returns physical memory of the system in bytes.
+func systemMemory() (uint64, error) {
+	s, err := syscall.Sysctl("hw.memsize")
+	if err != nil {
+		return 0, fmt.Errorf("hw.memsize: %w", err)
+	}
+
+	// Sysctl drops the last zero byte of little-endian uint64.
+	b := make([]byte, 8)
+	copy(b, s)
+
+	return binary.LittleEndian.Uint64(b), nil
+}
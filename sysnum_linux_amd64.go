@@ -0,0 +1,4 @@
+package numa
+
+// syscall package doesn't define getcpu for amd64.
+const sysGetcpu = 309
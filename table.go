@@ -0,0 +1,68 @@
+package numa
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Column represent column of nodes table.
+type Column struct {
+	Header string
+	Value  func(Node) string
+}
+
+// Columns of nodes table. Memory is human-readable, see FormatBytes.
+var (
+	ColumnNode         = Column{"NODE", func(n Node) string { return strconv.Itoa(n.ID) }}
+	ColumnCPUs         = Column{"CPUS", func(n Node) string { return NewCPUSet(n.CPU...).String() }}
+	ColumnCPUCount     = Column{"CPU COUNT", func(n Node) string { return strconv.Itoa(len(n.CPU)) }}
+	ColumnMemTotal     = Column{"TOTAL", func(n Node) string { return FormatBytes(n.MemTotal) }}
+	ColumnMemFree      = Column{"FREE", func(n Node) string { return FormatBytes(n.MemFree) }}
+	ColumnMemAvailable = Column{"AVAILABLE", func(n Node) string { return FormatBytes(n.MemAvailable) }}
+	ColumnMemUsed      = Column{"USED", func(n Node) string { return fmt.Sprintf("%.1f%%", n.MemUsedPercent()) }}
+	ColumnDistances    = Column{"DISTANCES", func(n Node) string { return joinInts(n.Distances) }}
+	ColumnNumaMiss     = Column{"NUMA MISS", func(n Node) string { return strconv.FormatUint(n.Stats.NumaMiss, 10) }}
+)
+
+// DefaultColumns are columns of WriteTable when none are given.
+var DefaultColumns = []Column{ColumnNode, ColumnCPUs, ColumnMemTotal, ColumnMemFree, ColumnMemAvailable}
+
+// WriteTable writes aligned table of nodes with columns to w.
+func WriteTable(w io.Writer, nodes []Node, columns ...Column) error {
+	if len(columns) == 0 {
+		columns = DefaultColumns
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	headers := make([]string, 0, len(columns))
+	for _, c := range columns {
+		headers = append(headers, c.Header)
+	}
+	if _, err := fmt.Fprintln(tw, strings.Join(headers, "\t")); err != nil {
+		return err
+	}
+
+	for _, n := range nodes {
+		values := make([]string, 0, len(columns))
+		for _, c := range columns {
+			values = append(values, c.Value(n))
+		}
+		if _, err := fmt.Fprintln(tw, strings.Join(values, "\t")); err != nil {
+			return err
+		}
+	}
+
+	return tw.Flush()
+}
+
+func joinInts(values []int) string {
+	s := make([]string, 0, len(values))
+	for _, v := range values {
+		s = append(s, strconv.Itoa(v))
+	}
+	return strings.Join(s, " ")
+}
@@ -0,0 +1,115 @@
+package numa
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Thread represent thread of process and node it last ran on.
+type Thread struct {
+	TID  int
+	Name string
+	// CPU is the CPU thread last ran on.
+	CPU int
+	// Node is node of CPU, -1 if unknown.
+	Node     int
+	Affinity CPUSet
+	// UserTime and SystemTime are CPU time in clock ticks.
+	UserTime   uint64
+	SystemTime uint64
+}
+
+// GetThreads returns threads of process with CPU and node they last ran on.
+// Pid 0 means the current process.
+func GetThreads(pid int) ([]Thread, error) {
+	cpuNodes, err := cpuNodeMap(newOptions(nil))
+	if err != nil {
+		return nil, fmt.Errorf("read cpu nodes: %w", err)
+	}
+
+	taskPath := procPath(pid, "task")
+	dir, err := os.ReadDir(taskPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var threads []Thread
+	for _, i := range dir {
+		tid, err := strconv.Atoi(i.Name())
+		if err != nil {
+			continue
+		}
+
+		t, err := parseThread(filepath.Join(taskPath, i.Name()), tid, cpuNodes)
+		if err != nil {
+			// Thread exited meanwhile.
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("thread %d: %w", tid, err)
+		}
+		threads = append(threads, t)
+	}
+
+	return threads, nil
+}
+
+func parseThread(path string, tid int, cpuNodes map[int]int) (Thread, error) {
+	stat, err := os.ReadFile(filepath.Join(path, "stat"))
+	if err != nil {
+		return Thread{}, err
+	}
+
+	// 1234 (name with spaces) S 1 ... processor is field 39.
+	start := strings.IndexByte(string(stat), '(')
+	end := strings.LastIndexByte(string(stat), ')')
+	if start < 0 || end < start {
+		return Thread{}, fmt.Errorf("invalid stat format: %q", string(stat))
+	}
+
+	// Fields after the name start from field 3.
+	fields := strings.Fields(string(stat[end+1:]))
+	if len(fields) < 39-2 {
+		return Thread{}, fmt.Errorf("invalid stat format: %q", string(stat))
+	}
+
+	cpu, err := strconv.Atoi(fields[39-3])
+	if err != nil {
+		return Thread{}, fmt.Errorf("convert processor %q: %w", fields[39-3], err)
+	}
+
+	// utime and stime are fields 14 and 15.
+	utime, err := strconv.ParseUint(fields[14-3], 10, 64)
+	if err != nil {
+		return Thread{}, fmt.Errorf("convert utime %q: %w", fields[14-3], err)
+	}
+	stime, err := strconv.ParseUint(fields[15-3], 10, 64)
+	if err != nil {
+		return Thread{}, fmt.Errorf("convert stime %q: %w", fields[15-3], err)
+	}
+
+	t := Thread{
+		TID:        tid,
+		Name:       string(stat[start+1 : end]),
+		CPU:        cpu,
+		Node:       -1,
+		UserTime:   utime,
+		SystemTime: stime,
+	}
+	if node, ok := cpuNodes[cpu]; ok {
+		t.Node = node
+	}
+
+	list, err := readStatusField(filepath.Join(path, "status"), "Cpus_allowed_list")
+	if err != nil {
+		return Thread{}, err
+	}
+	if t.Affinity, err = ParseCPUSet(list); err != nil {
+		return Thread{}, fmt.Errorf("parse Cpus_allowed_list: %w", err)
+	}
+
+	return t, nil
+}
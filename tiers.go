@@ -0,0 +1,76 @@
+package numa
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MemoryTier represent memory tier and its nodes.
+// Tiers with lower ID are faster.
+type MemoryTier struct {
+	ID    int
+	Nodes NodeSet
+}
+
+// GetMemoryTiers returns memory tiers sorted by ID.
+// It returns nil if kernel doesn't support memory tiering.
+func GetMemoryTiers(opts ...Option) ([]MemoryTier, error) {
+	return readMemoryTiers(newOptions(opts))
+}
+
+func readMemoryTiers(o options) ([]MemoryTier, error) {
+	tiersPath := "devices/virtual/memory_tiering"
+
+	dir, err := fs.ReadDir(o.sys, tiersPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var tiers []MemoryTier
+	for _, i := range dir {
+		// memory_tier4
+		if !strings.HasPrefix(i.Name(), "memory_tier") {
+			continue
+		}
+
+		id, err := strconv.Atoi(strings.TrimPrefix(i.Name(), "memory_tier"))
+		if err != nil {
+			return nil, fmt.Errorf("convert tier %q: %w", i.Name(), err)
+		}
+
+		f, err := fs.ReadFile(o.sys, path.Join(tiersPath, i.Name(), "nodelist"))
+		if err != nil {
+			return nil, err
+		}
+
+		nodes, err := ParseNodeSet(string(f))
+		if err != nil {
+			return nil, fmt.Errorf("parse nodelist of %s: %w", i.Name(), err)
+		}
+
+		tiers = append(tiers, MemoryTier{ID: id, Nodes: nodes})
+	}
+
+	sort.Slice(tiers, func(a, b int) bool {
+		return tiers[a].ID < tiers[b].ID
+	})
+
+	return tiers, nil
+}
+
+func memoryTierOf(tiers []MemoryTier, nodeID int) int {
+	for _, t := range tiers {
+		if t.Nodes.Contains(nodeID) {
+			return t.ID
+		}
+	}
+	return -1
+}
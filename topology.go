@@ -0,0 +1,143 @@
+package numa
+
+import (
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Topology holds nodes shared between goroutines. Accessors return copies
+// which callers can modify, readers don't block each other and are blocked
+// only while Refresh stores new nodes.
+type Topology struct {
+	opts []Option
+
+	mu        sync.RWMutex
+	nodes     []Node
+	emulation Emulation
+	updated   time.Time
+}
+
+// NewTopology returns Topology with nodes read with GetNodes options.
+func NewTopology(opts ...Option) (*Topology, error) {
+	t := &Topology{opts: opts}
+	if err := t.Refresh(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Refresh reads nodes again, previous nodes are kept if reading fails
+// including partial failure of some nodes.
+func (t *Topology) Refresh() error {
+	nodes, err := GetNodes(t.opts...)
+	if err != nil {
+		return err
+	}
+
+	emulation, err := detectEmulation(newOptions(t.opts), nodes)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.nodes = nodes
+	t.emulation = emulation
+	t.updated = time.Now()
+
+	return nil
+}
+
+// Updated returns time of the last successful Refresh.
+func (t *Topology) Updated() time.Time {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.updated
+}
+
+// Emulation returns NUMA emulation detected on the last successful Refresh,
+// see DetectEmulation.
+func (t *Topology) Emulation() Emulation {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.emulation
+}
+
+// Nodes returns copy of all nodes.
+func (t *Topology) Nodes() NodeList {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	nodes := make(NodeList, len(t.nodes))
+	for i, n := range t.nodes {
+		nodes[i] = n.clone()
+	}
+
+	return nodes
+}
+
+// Node returns copy of node id, false if there is no such node.
+func (t *Topology) Node(id int) (Node, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for _, n := range t.nodes {
+		if n.ID == id {
+			return n.clone(), true
+		}
+	}
+
+	return Node{}, false
+}
+
+// NodeIDs returns sorted IDs of all nodes.
+func (t *Topology) NodeIDs() []int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	ids := make([]int, len(t.nodes))
+	for i, n := range t.nodes {
+		ids[i] = n.ID
+	}
+	slices.Sort(ids)
+
+	return ids
+}
+
+// NodeForCPU returns node of CPU, false if CPU doesn't belong to any node.
+func (t *Topology) NodeForCPU(cpu int) (int, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for _, n := range t.nodes {
+		if slices.Contains(n.CPU, cpu) {
+			return n.ID, true
+		}
+	}
+
+	return -1, false
+}
+
+// clone returns deep copy of node.
+func (n Node) clone() Node {
+	n.CPU = slices.Clone(n.CPU)
+	n.Distances = slices.Clone(n.Distances)
+	n.DistanceNodes = slices.Clone(n.DistanceNodes)
+	n.Hugepages = maps.Clone(n.Hugepages)
+	n.MemInfo.Raw = maps.Clone(n.MemInfo.Raw)
+	n.MemorySideCaches = slices.Clone(n.MemorySideCaches)
+	n.Zones = slices.Clone(n.Zones)
+
+	n.Access = slices.Clone(n.Access)
+	for i, a := range n.Access {
+		n.Access[i].Initiators = NodeSet{mask: slices.Clone(a.Initiators.mask)}
+	}
+
+	return n
+}
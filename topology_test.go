@@ -0,0 +1,42 @@
+package numa
+
+import "testing"
+
+func TestNodeCloneDeep(t *testing.T) {
+	n := Node{
+		ID:            0,
+		CPU:           []int{0, 1},
+		Distances:     []int{10, 20},
+		DistanceNodes: []int{0, 1},
+		Hugepages:     map[uint64]Hugepages{2 << 20: {Total: 1}},
+		MemInfo:       MemInfo{Raw: map[string]uint64{"MemTotal": 1}},
+		Zones:         []Zone{{Name: "Normal"}},
+		Access:        []AccessAttributes{{Initiators: NewNodeSet(0)}},
+	}
+
+	c := n.clone()
+	c.CPU[0] = 9
+	c.Distances[0] = 9
+	c.DistanceNodes[0] = 9
+	c.Hugepages[2<<20] = Hugepages{Total: 9}
+	c.MemInfo.Raw["MemTotal"] = 9
+	c.Zones[0].Name = "DMA"
+	c.Access[0].Initiators.Add(1)
+
+	switch {
+	case n.CPU[0] != 0:
+		t.Error("CPU is shared with clone")
+	case n.Distances[0] != 10:
+		t.Error("Distances are shared with clone")
+	case n.DistanceNodes[0] != 0:
+		t.Error("DistanceNodes are shared with clone")
+	case n.Hugepages[2<<20].Total != 1:
+		t.Error("Hugepages are shared with clone")
+	case n.MemInfo.Raw["MemTotal"] != 1:
+		t.Error("MemInfo.Raw is shared with clone")
+	case n.Zones[0].Name != "Normal":
+		t.Error("Zones are shared with clone")
+	case n.Access[0].Initiators.String() != "0":
+		t.Error("Access initiators are shared with clone")
+	}
+}
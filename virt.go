@@ -0,0 +1,192 @@
+package numa
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Virtualization describe hypervisor detected by DetectVirtualization.
+// NUMA topology of guest is virtual (vNUMA): guest nodes could be backed by
+// memory and CPUs of any host node, so tuning against it could be wrong for the host.
+type Virtualization struct {
+	// Virtual is set if the system runs under hypervisor.
+	Virtual bool `json:"virtual" yaml:"virtual"`
+	// Hypervisor is name of hypervisor if it's known, e.g. "KVM", "VMware" or "Xen".
+	Hypervisor string `json:"hypervisor,omitempty" yaml:"hypervisor,omitempty"`
+	// Source is how hypervisor was detected: "cpuid", "hypervisor" or "dmi".
+	Source string `json:"source,omitempty" yaml:"source,omitempty"`
+	// Warnings describe node topology inconsistent with CPU topology
+	// reported by CPUID leaves, e.g. node with CPUs of several packages.
+	Warnings []string `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+}
+
+// dmiHypervisors are hypervisor names keyed by substrings of DMI sys_vendor
+// or product_name. Vendors of both virtual and bare metal machines,
+// e.g. "Amazon EC2", are detected with CPUID only.
+var dmiHypervisors = []struct {
+	match string
+	name  string
+}{
+	{"QEMU", "KVM"},
+	{"KVM", "KVM"},
+	{"VMware", "VMware"},
+	{"Virtual Machine", "Hyper-V"},
+	{"Xen", "Xen"},
+	{"VirtualBox", "VirtualBox"},
+	{"innotek", "VirtualBox"},
+	{"Parallels", "Parallels"},
+	{"Bochs", "Bochs"},
+	{"Google Compute Engine", "KVM"},
+}
+
+// DetectVirtualization reports whether nodes are virtual using hypervisor flag
+// of /proc/cpuinfo set from CPUID, /sys/hypervisor and DMI identification.
+// Nodes of guests are compared with packages of CPUs and Warnings are set on mismatches.
+func DetectVirtualization(opts ...Option) (Virtualization, error) {
+	o := newOptions(opts)
+
+	var v Virtualization
+
+	hypervisorFlag, err := cpuinfoHasFlag(o, "hypervisor")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return Virtualization{}, fmt.Errorf("read cpuinfo: %w", err)
+	}
+	if hypervisorFlag {
+		v.Virtual, v.Source = true, "cpuid"
+	}
+
+	// Xen reports itself in sysfs even for PV guests without CPUID flag.
+	if b, err := fs.ReadFile(o.sys, "hypervisor/type"); err == nil {
+		if name := strings.TrimSpace(string(b)); name != "" {
+			v.Hypervisor = strings.ToUpper(name[:1]) + name[1:]
+			if !v.Virtual {
+				v.Virtual, v.Source = true, "hypervisor"
+			}
+		}
+	}
+
+	if v.Hypervisor == "" {
+		if name := dmiHypervisor(o); name != "" {
+			v.Hypervisor = name
+			if !v.Virtual {
+				v.Virtual, v.Source = true, "dmi"
+			}
+		}
+	}
+
+	if !v.Virtual {
+		return v, nil
+	}
+
+	nodes, err := GetNodes(opts...)
+	if errors.Is(err, ErrNoNUMA) {
+		return v, nil
+	}
+	if err != nil {
+		return Virtualization{}, err
+	}
+
+	v.Warnings = virtualTopologyWarnings(o, nodes)
+	for _, w := range v.Warnings {
+		o.debug("virtual NUMA topology mismatch", "warning", w)
+	}
+
+	return v, nil
+}
+
+// cpuinfoHasFlag reports whether flags of the first CPU in /proc/cpuinfo contain flag.
+func cpuinfoHasFlag(o options, flag string) (bool, error) {
+	f, err := o.proc.Open("cpuinfo")
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// flags		: fpu vme de pse ... hypervisor lahf_lm
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok || strings.TrimSpace(key) != "flags" {
+			continue
+		}
+		return slices.Contains(strings.Fields(value), flag), nil
+	}
+
+	return false, scanner.Err()
+}
+
+// dmiHypervisor returns hypervisor name matching DMI identification, empty if
+// it's unknown or DMI isn't available, e.g. on most arm64 machines.
+func dmiHypervisor(o options) string {
+	for _, name := range []string{"sys_vendor", "product_name"} {
+		b, err := fs.ReadFile(o.sys, path.Join("class/dmi/id", name))
+		if err != nil {
+			continue
+		}
+		for _, h := range dmiHypervisors {
+			if strings.Contains(string(b), h.match) {
+				return h.name
+			}
+		}
+	}
+
+	return ""
+}
+
+// virtualTopologyWarnings compares nodes with CPU packages and distances
+// which are often inconsistent in hand-written vNUMA configurations.
+func virtualTopologyWarnings(o options, nodes []Node) []string {
+	var warnings []string
+
+	var allPackages NodeSet
+	for _, n := range nodes {
+		var packages NodeSet
+		for _, cpu := range n.CPU {
+			info, err := getCPUInfo(o, cpu)
+			if err != nil {
+				o.debug("skip package check of cpu", "cpu", cpu, "err", err)
+				continue
+			}
+			packages.Add(info.PackageID)
+		}
+
+		if packages.Len() > 1 {
+			warnings = append(warnings, fmt.Sprintf("node %d has CPUs of packages %s", n.ID, packages))
+		}
+		allPackages = allPackages.Union(packages)
+	}
+
+	if len(nodes) > 1 && allPackages.Len() == 1 {
+		warnings = append(warnings, fmt.Sprintf("%d nodes share single CPU package", len(nodes)))
+	}
+
+	if len(nodes) > 2 && uniformDistances(nodes) {
+		warnings = append(warnings, "distances between all nodes are equal, host distances are likely not exposed")
+	}
+
+	return warnings
+}
+
+// uniformDistances reports whether all remote distances of nodes are equal.
+func uniformDistances(nodes []Node) bool {
+	remote := -1
+	for _, n := range nodes {
+		ids := n.distanceIDs()
+		for i, d := range n.Distances {
+			if ids[i] == n.ID {
+				continue
+			}
+			if remote != -1 && d != remote {
+				return false
+			}
+			remote = d
+		}
+	}
+
+	return remote != -1
+}
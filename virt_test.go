@@ -0,0 +1,36 @@
+package numa
+
+import "testing"
+
+func TestUniformDistances(t *testing.T) {
+	tests := []struct {
+		name  string
+		nodes []Node
+		want  bool
+	}{
+		{"uniform", []Node{
+			{ID: 0, Distances: []int{10, 20, 20}},
+			{ID: 1, Distances: []int{20, 10, 20}},
+			{ID: 2, Distances: []int{20, 20, 10}},
+		}, true},
+		{"distinct", []Node{
+			{ID: 0, Distances: []int{10, 20, 30}},
+			{ID: 1, Distances: []int{20, 10, 20}},
+			{ID: 2, Distances: []int{30, 20, 10}},
+		}, false},
+		// Local distance of node 2 is the second column, not the third.
+		{"sparse IDs", []Node{
+			{ID: 0, Distances: []int{10, 20, 20}, DistanceNodes: []int{0, 2, 3}},
+			{ID: 2, Distances: []int{20, 10, 20}, DistanceNodes: []int{0, 2, 3}},
+			{ID: 3, Distances: []int{20, 20, 10}, DistanceNodes: []int{0, 2, 3}},
+		}, true},
+		{"single node", []Node{{ID: 0, Distances: []int{10}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := uniformDistances(tt.nodes); got != tt.want {
+				t.Errorf("uniformDistances() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
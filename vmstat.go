@@ -0,0 +1,101 @@
+package numa
+
+import (
+	"fmt"
+	"path"
+)
+
+// VMStat represent NUMA node virtual memory counters in pages.
+type VMStat struct {
+	NrFreePages              uint64
+	NrInactiveAnon           uint64
+	NrActiveAnon             uint64
+	NrInactiveFile           uint64
+	NrActiveFile             uint64
+	NrUnevictable            uint64
+	NrSlabReclaimable        uint64
+	NrSlabUnreclaimable      uint64
+	NrIsolatedAnon           uint64
+	NrIsolatedFile           uint64
+	NrAnonPages              uint64
+	NrMapped                 uint64
+	NrFilePages              uint64
+	NrDirty                  uint64
+	NrWriteback              uint64
+	NrShmem                  uint64
+	NrAnonTransparentHuge    uint64
+	NrKernelStack            uint64
+	NrPageTablePages         uint64
+	NrMlock                  uint64
+	WorkingsetNodes          uint64
+	WorkingsetRefaultAnon    uint64
+	WorkingsetRefaultFile    uint64
+	WorkingsetActivateAnon   uint64
+	WorkingsetActivateFile   uint64
+	WorkingsetRestoreAnon    uint64
+	WorkingsetRestoreFile    uint64
+	WorkingsetNodeReclaim    uint64
+	NumaHit                  uint64
+	NumaMiss                 uint64
+	NumaForeign              uint64
+	NumaInterleave           uint64
+	NumaLocal                uint64
+	NumaOther                uint64
+	PgPromoteSuccess         uint64
+	PgDemoteKswapd           uint64
+	PgDemoteDirect           uint64
+	NrVmscanWrite            uint64
+	NrVmscanImmediateReclaim uint64
+}
+
+// GetNodeVMStat returns virtual memory counters of NUMA node.
+func GetNodeVMStat(nodeID int, opts ...Option) (VMStat, error) {
+	o := newOptions(opts)
+
+	v, err := parseKeyValues(o, path.Join("devices/system/node", fmt.Sprintf("node%d", nodeID), "vmstat"))
+	if err != nil {
+		return VMStat{}, fmt.Errorf("parse vmstat: %w", err)
+	}
+
+	return VMStat{
+		NrFreePages:              v["nr_free_pages"],
+		NrInactiveAnon:           v["nr_inactive_anon"],
+		NrActiveAnon:             v["nr_active_anon"],
+		NrInactiveFile:           v["nr_inactive_file"],
+		NrActiveFile:             v["nr_active_file"],
+		NrUnevictable:            v["nr_unevictable"],
+		NrSlabReclaimable:        v["nr_slab_reclaimable"],
+		NrSlabUnreclaimable:      v["nr_slab_unreclaimable"],
+		NrIsolatedAnon:           v["nr_isolated_anon"],
+		NrIsolatedFile:           v["nr_isolated_file"],
+		NrAnonPages:              v["nr_anon_pages"],
+		NrMapped:                 v["nr_mapped"],
+		NrFilePages:              v["nr_file_pages"],
+		NrDirty:                  v["nr_dirty"],
+		NrWriteback:              v["nr_writeback"],
+		NrShmem:                  v["nr_shmem"],
+		NrAnonTransparentHuge:    v["nr_anon_transparent_hugepages"],
+		NrKernelStack:            v["nr_kernel_stack"],
+		NrPageTablePages:         v["nr_page_table_pages"],
+		NrMlock:                  v["nr_mlock"],
+		WorkingsetNodes:          v["workingset_nodes"],
+		WorkingsetRefaultAnon:    v["workingset_refault_anon"],
+		WorkingsetRefaultFile:    v["workingset_refault_file"],
+		WorkingsetActivateAnon:   v["workingset_activate_anon"],
+		WorkingsetActivateFile:   v["workingset_activate_file"],
+		WorkingsetRestoreAnon:    v["workingset_restore_anon"],
+		WorkingsetRestoreFile:    v["workingset_restore_file"],
+		WorkingsetNodeReclaim:    v["workingset_nodereclaim"],
+		NumaHit:                  v["numa_hit"],
+		NumaMiss:                 v["numa_miss"],
+		NumaForeign:              v["numa_foreign"],
+		NumaInterleave:           v["numa_interleave"],
+		NumaLocal:                v["numa_local"],
+		NumaOther:                v["numa_other"],
+		PgPromoteSuccess:         v["pgpromote_success"],
+		PgDemoteKswapd:           v["pgdemote_kswapd"],
+		PgDemoteDirect:           v["pgdemote_direct"],
+		NrVmscanWrite:            v["nr_vmscan_write"],
+		NrVmscanImmediateReclaim: v["nr_vmscan_immediate_reclaim"],
+	}, nil
+}
@@ -0,0 +1,128 @@
+package numa
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Snapshot represent nodes information at a point in time and changes
+// of topology since previous snapshot.
+type Snapshot struct {
+	Time  time.Time
+	Nodes []Node
+	// Added and Removed are IDs of nodes which appeared or disappeared.
+	Added   []int
+	Removed []int
+	// Changed are IDs of nodes whose CPUs or total memory changed.
+	Changed []int
+	// Err is error of reading nodes, Nodes contain only nodes read successfully then.
+	Err error
+}
+
+// TopologyChanged reports whether nodes were added, removed or changed.
+func (s Snapshot) TopologyChanged() bool {
+	return len(s.Added) > 0 || len(s.Removed) > 0 || len(s.Changed) > 0
+}
+
+// Watcher periodically reads nodes information and delivers snapshots to C.
+type Watcher struct {
+	C <-chan Snapshot
+
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+// NewWatcher starts reading nodes every interval with GetNodes options,
+// non-positive interval means 10 seconds. Call Stop to release resources.
+func NewWatcher(interval time.Duration, opts ...Option) *Watcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Watcher{C: WatchNodes(ctx, interval, opts...), cancel: cancel}
+}
+
+// Stop stops the watcher and closes C.
+func (w *Watcher) Stop() {
+	w.once.Do(w.cancel)
+}
+
+// WatchNodes reads nodes every interval with GetNodes options and sends snapshots
+// to the returned channel. With WithChangesOnly only snapshots with topology
+// changes or errors are sent. The channel is closed when ctx is done.
+// Non-positive interval means 10 seconds.
+func WatchNodes(ctx context.Context, interval time.Duration, opts ...Option) <-chan Snapshot {
+	o := newOptions(opts)
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	snapshots := make(chan Snapshot)
+	go func() {
+		defer close(snapshots)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var previous []Node
+		first := true
+		for {
+			s := Snapshot{Time: time.Now()}
+			s.Nodes, s.Err = GetNodesContext(ctx, opts...)
+			if ctx.Err() != nil {
+				return
+			}
+			if s.Err == nil {
+				s.Added, s.Removed, s.Changed = diffNodes(previous, s.Nodes)
+				previous = s.Nodes
+			}
+
+			if first || s.Err != nil || !o.changesOnly || s.TopologyChanged() {
+				select {
+				case snapshots <- s:
+				case <-ctx.Done():
+					return
+				}
+			}
+			first = false
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return snapshots
+}
+
+// diffNodes returns IDs of added, removed and changed nodes.
+func diffNodes(previous, current []Node) ([]int, []int, []int) {
+	byID := make(map[int]Node, len(previous))
+	for _, n := range previous {
+		byID[n.ID] = n
+	}
+
+	var added, changed []int
+	for _, n := range current {
+		p, ok := byID[n.ID]
+		delete(byID, n.ID)
+
+		if !ok {
+			added = append(added, n.ID)
+			continue
+		}
+		if p.MemTotal != n.MemTotal || !reflect.DeepEqual(p.CPU, n.CPU) {
+			changed = append(changed, n.ID)
+		}
+	}
+
+	var removed []int
+	for _, n := range previous {
+		if _, ok := byID[n.ID]; ok {
+			removed = append(removed, n.ID)
+		}
+	}
+
+	return added, removed, changed
+}
@@ -0,0 +1,123 @@
+package numa
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// sequenceProvider returns the next nodes of sequence on every Nodes call,
+// the last nodes are repeated.
+type sequenceProvider struct {
+	mu       sync.Mutex
+	sequence [][]Node
+}
+
+func (p *sequenceProvider) Nodes(context.Context) ([]Node, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	nodes := p.sequence[0]
+	if len(p.sequence) > 1 {
+		p.sequence = p.sequence[1:]
+	}
+	return nodes, nil
+}
+
+func (p *sequenceProvider) Distances(ctx context.Context) ([][]int, error) {
+	nodes, err := p.Nodes(ctx)
+	return nodesDistances(nodes), err
+}
+
+func (p *sequenceProvider) Stats(ctx context.Context) (map[int]NodeStats, error) {
+	nodes, err := p.Nodes(ctx)
+	return nodesStats(nodes), err
+}
+
+func TestDiffNodes(t *testing.T) {
+	tests := []struct {
+		name                    string
+		previous, current       []Node
+		added, removed, changed []int
+	}{
+		{name: "first", current: []Node{{ID: 0}, {ID: 1}}, added: []int{0, 1}},
+		{name: "same", previous: []Node{{ID: 0, CPU: []int{0}}}, current: []Node{{ID: 0, CPU: []int{0}}}},
+		{name: "free memory only", previous: []Node{{ID: 0, MemFree: 1}}, current: []Node{{ID: 0, MemFree: 2}}},
+		{name: "removed", previous: []Node{{ID: 0}, {ID: 1}}, current: []Node{{ID: 0}}, removed: []int{1}},
+		{name: "cpus", previous: []Node{{ID: 0, CPU: []int{0}}}, current: []Node{{ID: 0, CPU: []int{0, 1}}}, changed: []int{0}},
+		{name: "total memory", previous: []Node{{ID: 0, MemTotal: 1}}, current: []Node{{ID: 0, MemTotal: 2}}, changed: []int{0}},
+		{name: "all", previous: []Node{{ID: 0}, {ID: 1, MemTotal: 1}}, current: []Node{{ID: 1}, {ID: 2}}, added: []int{2}, removed: []int{0}, changed: []int{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added, removed, changed := diffNodes(tt.previous, tt.current)
+			if !slices.Equal(added, tt.added) || !slices.Equal(removed, tt.removed) || !slices.Equal(changed, tt.changed) {
+				t.Errorf("diffNodes() = %v, %v, %v, want %v, %v, %v", added, removed, changed, tt.added, tt.removed, tt.changed)
+			}
+		})
+	}
+}
+
+func TestWatchNodesChangesOnly(t *testing.T) {
+	p := &sequenceProvider{sequence: [][]Node{
+		{{ID: 0}},
+		{{ID: 0}},
+		{{ID: 0}, {ID: 1}},
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := WatchNodes(ctx, time.Millisecond, WithProvider(p), WithChangesOnly())
+
+	if s := <-c; !slices.Equal(s.Added, []int{0}) {
+		t.Errorf("first snapshot added = %v, want [0]", s.Added)
+	}
+	// Unchanged second read isn't sent.
+	if s := <-c; !slices.Equal(s.Added, []int{1}) {
+		t.Errorf("second snapshot added = %v, want [1]", s.Added)
+	}
+}
+
+func TestWatcherStop(t *testing.T) {
+	p := &sequenceProvider{sequence: [][]Node{{{ID: 0}}}}
+
+	w := NewWatcher(time.Millisecond, WithProvider(p))
+	<-w.C
+	w.Stop()
+	w.Stop()
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-w.C:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("C isn't closed after Stop")
+		}
+	}
+}
+
+func TestWatchNodesContextDone(t *testing.T) {
+	p := &sequenceProvider{sequence: [][]Node{{{ID: 0}}}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := WatchNodes(ctx, 0, WithProvider(p))
+	<-c
+	cancel()
+
+	select {
+	case _, ok := <-c:
+		if ok {
+			// Snapshot could be read before cancel was noticed.
+			if _, ok := <-c; ok {
+				t.Error("channel isn't closed after context is done")
+			}
+		}
+	case <-time.After(time.Second):
+		t.Fatal("channel isn't closed after context is done")
+	}
+}
@@ -0,0 +1,116 @@
+package numa
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Zone represent memory zone of NUMA node. Values are in pages.
+type Zone struct {
+	Node    int    `json:"node" yaml:"node"`
+	Name    string `json:"name" yaml:"name"`
+	Free    uint64 `json:"free" yaml:"free"`
+	Min     uint64 `json:"min" yaml:"min"`
+	Low     uint64 `json:"low" yaml:"low"`
+	High    uint64 `json:"high" yaml:"high"`
+	Spanned uint64 `json:"spanned" yaml:"spanned"`
+	Present uint64 `json:"present" yaml:"present"`
+	Managed uint64 `json:"managed" yaml:"managed"`
+}
+
+// GetZoneInfo returns memory zones of all NUMA nodes.
+func GetZoneInfo(opts ...Option) ([]Zone, error) {
+	return readZoneInfo(newOptions(opts))
+}
+
+func readZoneInfo(o options) ([]Zone, error) {
+	f, err := o.proc.Open("zoneinfo")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var zones []Zone
+	var zone *Zone
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		// Node 0, zone   Normal
+		if strings.HasPrefix(line, "Node ") {
+			var id int
+			var name string
+			if _, err := fmt.Sscanf(line, "Node %d, zone %s", &id, &name); err != nil {
+				if err := o.malformedValue("zoneinfo", fmt.Errorf("parse zone header %q: %w", line, err)); err != nil {
+					return nil, err
+				}
+				// Values of skipped zone aren't assigned to the previous one.
+				zone = nil
+				continue
+			}
+			zones = append(zones, Zone{Node: id, Name: name})
+			zone = &zones[len(zones)-1]
+			continue
+		}
+
+		if zone == nil {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		//   pages free     3840
+		if len(fields) == 3 && fields[0] == "pages" {
+			fields = fields[1:]
+		}
+		if len(fields) != 2 {
+			continue
+		}
+
+		var value *uint64
+		switch fields[0] {
+		case "free":
+			value = &zone.Free
+		case "min":
+			value = &zone.Min
+		case "low":
+			value = &zone.Low
+		case "high":
+			value = &zone.High
+		case "spanned":
+			value = &zone.Spanned
+		case "present":
+			value = &zone.Present
+		case "managed":
+			value = &zone.Managed
+		default:
+			continue
+		}
+
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			if err := o.malformedValue("zoneinfo", fmt.Errorf("convert %s %q: %w", fields[0], fields[1], err)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		*value = v
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return zones, nil
+}
+
+func nodeZones(zones []Zone, nodeID int) []Zone {
+	var nodeZones []Zone
+	for _, z := range zones {
+		if z.Node == nodeID {
+			nodeZones = append(nodeZones, z)
+		}
+	}
+	return nodeZones
+}